@@ -3,25 +3,81 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"golang.org/x/crypto/bcrypt"
 
 	_ "github.com/Duncan-Kiragu/Msaada-Backend/configs"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/infra/database"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/infra/handlers"
-	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/hasher"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
 	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
 )
 
+// headerRealIP Header the proxies set to the address of their client.
+const headerRealIP = "X-Real-IP"
+
+// newPasswordHasher Selects the hasher of the new passwords by 'PASSWORD_HASHER', argon2id unless it is 'bcrypt'.
+// The argon2id parameters are read from 'ARGON2_MEMORY' (KiB), 'ARGON2_TIME' and 'ARGON2_THREADS', the
+// bcrypt cost from 'BCRYPT_COST'.
+func newPasswordHasher() hasher.Hasher {
+	envUint := func(env string, value uint64, bits int) uint64 {
+		if configured, err := strconv.ParseUint(os.Getenv(env), 10, bits); err == nil && configured > 0 {
+			return configured
+		}
+		return value
+	}
+
+	if strings.ToLower(os.Getenv("PASSWORD_HASHER")) == "bcrypt" {
+		return &hasher.Bcrypt{Cost: max(int(envUint("BCRYPT_COST", uint64(bcrypt.DefaultCost), 5)), bcrypt.MinCost)}
+	}
+
+	params := hasher.DefaultArgon2id()
+	params.Memory = uint32(envUint("ARGON2_MEMORY", uint64(params.Memory), 32))
+	params.Time = uint32(envUint("ARGON2_TIME", uint64(params.Time), 32))
+	params.Threads = uint8(envUint("ARGON2_THREADS", uint64(params.Threads), 8))
+	return params
+}
+
+// corsConfig Allows any origin unless 'API_CORS_ORIGINS' lists them, separated by commas. Explicit
+// origins are allowed to send credentials, the cookies of the 'AUTH_COOKIE' mode, so the wildcards
+// are replaced by the headers used by the API.
+func corsConfig() cors.Config {
+	config := cors.Config{
+		AllowOrigins:  "*",
+		AllowMethods:  strings.Join([]string{fiber.MethodGet, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete, fiber.MethodOptions}, ","),
+		AllowHeaders:  "*",
+		ExposeHeaders: "*",
+		MaxAge:        1,
+	}
+
+	origins := []string{}
+	for _, origin := range strings.Split(os.Getenv("API_CORS_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" && origin != "*" {
+			origins = append(origins, origin)
+		}
+	}
+
+	if len(origins) > 0 {
+		config.AllowOrigins = strings.Join(origins, ",")
+		config.AllowCredentials = true
+		config.AllowHeaders = strings.Join([]string{fiber.HeaderOrigin, fiber.HeaderContentType, fiber.HeaderAccept, fiber.HeaderAcceptLanguage, fiber.HeaderAuthorization, middleware.HeaderCsrf}, ",")
+		config.ExposeHeaders = strings.Join([]string{fiber.HeaderContentDisposition, fiber.HeaderRetryAfter, fiber.HeaderXRequestID, "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"}, ",")
+	}
+
+	return config
+}
+
 // @title 							Go - Template API
 // @description 					Template API.
 
@@ -34,27 +90,65 @@ import (
 // @in								header
 // @name							Authorization
 // @description 					Type "Bearer" followed by a space and JWT token.
+
+// @securityDefinitions.apiKey		ApiKey
+// @in								header
+// @name							Authorization
+// @description 					Type "ApiKey" followed by a space and the API key.
 func main() {
+	hasher.Default = newPasswordHasher()
+	// The cursor mode of the lists is enabled by 'CURSOR_SECRET', shared by every instance.
+	if secret := os.Getenv("CURSOR_SECRET"); secret != "" {
+		filter.CursorSecret = []byte(secret)
+	}
+
 	postgresdb, err := database.ConnectPostgresDB()
 	helpers.PanicIfErr(err)
 
+	// Behind the proxies of 'API_TRUSTED_PROXIES' the client address is read from 'API_PROXY_HEADER',
+	// 'X-Real-IP' by default, which the proxy must overwrite. A 'X-Forwarded-For' is read right to left.
+	trustedProxies := []string{}
+	for _, proxy := range strings.Split(os.Getenv("API_TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	proxyHeader := ""
+	if len(trustedProxies) > 0 {
+		proxyHeader = os.Getenv("API_PROXY_HEADER")
+		if proxyHeader == "" {
+			proxyHeader = headerRealIP
+		}
+	}
+	forwardedFor := strings.EqualFold(proxyHeader, fiber.HeaderXForwardedFor)
+
 	app := fiber.New(fiber.Config{
-		EnablePrintRoutes:     false,
-		Prefork:               os.Getenv("SYS_PREFORK") == "true",
-		CaseSensitive:         true,
-		StrictRouting:         true,
-		DisableStartupMessage: false,
-		AppName:               "Go - Template API",
-		ReduceMemoryUsage:     false,
+		EnablePrintRoutes:       false,
+		ProxyHeader:             proxyHeader,
+		EnableTrustedProxyCheck: len(trustedProxies) > 0,
+		TrustedProxies:          trustedProxies,
+		EnableIPValidation:      true,
+		Prefork:                 os.Getenv("SYS_PREFORK") == "true",
+		CaseSensitive:           true,
+		StrictRouting:           true,
+		DisableStartupMessage:   false,
+		AppName:                 "Go - Template API",
+		ReduceMemoryUsage:       false,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, err)
 		},
 	})
 
+	app.Use(recover.New())
+	if forwardedFor {
+		app.Use(middleware.ResolveForwardedFor(httphelper.ParseTrustedProxies(trustedProxies)))
+	}
+
 	app.Use(
-		recover.New(),
 		middleware.GetRequestLanguage,
-		requestid.New(),
+		middleware.GetRequestIP,
+		requestid.New(requestid.Config{ContextKey: httphelper.LocalRequestID}),
 	)
 
 	if strings.ToLower(os.Getenv("API_LOGGER")) == "true" {
@@ -73,24 +167,7 @@ func main() {
 		}))
 	}
 
-	app.Use(
-		cors.New(cors.Config{
-			AllowOrigins:  "*",
-			AllowMethods:  strings.Join([]string{fiber.MethodGet, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete, fiber.MethodOptions}, ","),
-			AllowHeaders:  "*",
-			ExposeHeaders: "*",
-			MaxAge:        1,
-			// AllowCredentials: true,
-		}),
-		limiter.New(limiter.Config{
-			Max:        200,
-			Expiration: time.Minute,
-			LimitReached: func(c *fiber.Ctx) error {
-				messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-				return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrManyRequest)
-			},
-		}),
-	)
+	app.Use(cors.New(corsConfig()))
 
 	handlers.HandleRequests(app, postgresdb)
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/Duncan-Kiragu/Msaada-Backend/configs"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/infra/database"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/repository"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+)
+
+// defaultRetain Time the retired refresh keys keep verifying tokens when their expiration is not configured.
+const defaultRetain = 24 * time.Hour
+
+// retainFor Returns how long the retired key must keep verifying the tokens it signed, the instances
+// take up to 'SigningKeyRefresh' to stop signing with it. The access tokens always expire, and the
+// refresh keys are also kept while the sessions that never expire reference them.
+func retainFor(use string, retain time.Duration) time.Duration {
+	if retain <= 0 {
+		retain = domain.AccessTokenLife()
+		if use == domain.KeyRefresh {
+			retain = defaultRetain
+			if life, err := helpers.DurationFromString(os.Getenv("RFRESH_TOKEN_EXPIRE"), time.Minute); err == nil {
+				retain = life
+			}
+		}
+	}
+
+	return retain + domain.SigningKeyRefresh
+}
+
+// Generates a new RSA key for the tokens, makes it the current one and retires the previous.
+//
+//	go run cmd/rotate-keys/rotate-keys.go -use access -retain 2h
+func main() {
+	use := flag.String("use", "all", "Tokens whose key is rotated: access, refresh or all")
+	bits := flag.Int("bits", 2048, "Size of the new RSA keys")
+	retain := flag.Duration("retain", 0, "Time the retired keys keep verifying tokens, defaults to the token expiration")
+	flag.Parse()
+
+	uses := []string{*use}
+	switch *use {
+	case "all":
+		uses = []string{domain.KeyAccess, domain.KeyRefresh}
+	case domain.KeyAccess, domain.KeyRefresh:
+	default:
+		log.Fatalf("invalid key use: %s", *use)
+	}
+
+	postgresdb, err := database.ConnectPostgresDB()
+	helpers.PanicIfErr(err)
+	repo := repository.NewSigningKeyRepository(postgresdb)
+
+	for _, use := range uses {
+		key, err := keyring.GenerateKey(*bits)
+		helpers.PanicIfErr(err)
+
+		retireAt := time.Now().Add(retainFor(use, *retain))
+		helpers.PanicIfErr(repo.RotateSigningKey(context.Background(), domain.NewSigningKey(use, key), retireAt))
+		log.Printf("%s key %s is the current one, the previous is retired at %s", use, key.ID, retireAt.Format(time.RFC3339))
+	}
+}
@@ -41,108 +41,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth": {
+        "/.well-known/jwks.json": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "User authenticated",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Public keys that verify the access tokens, selected by the 'kid' header of the token",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "User authenticated",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User token",
-                        "name": "Authorization",
-                        "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Language responses",
-                        "name": "lang",
-                        "in": "query"
-                    }
-                ],
+                "summary": "JSON Web Key Set",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserOutputDTO"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/keyring.JWKS"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "User refresh",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "User refresh",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User token",
-                        "name": "Authorization",
-                        "in": "header"
-                    },
+            }
+        },
+        "/apikey": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Language responses",
-                        "name": "lang",
-                        "in": "query"
+                        "Bearer": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.AuthOutputDTO"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "User authentication",
+                "description": "Get API keys",
                 "consumes": [
                     "application/json"
                 ],
@@ -150,9 +76,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "ApiKey"
                 ],
-                "summary": "User authentication",
+                "summary": "Get API keys",
                 "parameters": [
                     {
                         "type": "string",
@@ -161,60 +87,15 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "description": "Credentials model",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.AuthInputDTO"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.AuthOutputDTO"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
-                        }
+                        "type": "boolean",
+                        "example": true,
+                        "name": "count",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/product": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Get products",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Product"
-                ],
-                "summary": "Get products",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Language responses",
-                        "name": "lang",
+                        "example": "empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
@@ -243,9 +124,81 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "'updated_at', 'created_at', 'name' or some other field of the response object",
+                        "example": "'like' by default, or 'fulltext' ranked by relevance",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'updated_at', 'created_at', 'name' or some other sortable field of the resource",
                         "name": "sort",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "profile_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "expires_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "last_used",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "created_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "updated_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations embedded, comma separated: 'profile' (default)",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -258,6 +211,18 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -272,7 +237,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Insert product",
+                "description": "Insert API key, the generated key is only returned in this response",
                 "consumes": [
                     "application/json"
                 ],
@@ -280,9 +245,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "ApiKey"
                 ],
-                "summary": "Insert product",
+                "summary": "Insert API key",
                 "parameters": [
                     {
                         "type": "string",
@@ -291,12 +256,12 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "description": "Product model",
-                        "name": "product",
+                        "description": "API key model",
+                        "name": "apikey",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductInputDTO"
+                            "$ref": "#/definitions/dto.ApiKeyInputDTO"
                         }
                     }
                 ],
@@ -304,7 +269,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductOutputDTO"
+                            "$ref": "#/definitions/dto.ApiKeyOutputDTO"
                         }
                     },
                     "400": {
@@ -313,8 +278,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
@@ -328,14 +293,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/product/{id}": {
+        "/apikey/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get product by ID",
+                "description": "Get API key by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -343,9 +308,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "ApiKey"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get API key by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -355,17 +320,23 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "API key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductOutputDTO"
+                            "$ref": "#/definitions/dto.ApiKeyOutputDTO"
                         }
                     },
                     "400": {
@@ -374,6 +345,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -394,7 +371,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update product by ID",
+                "description": "Update API key by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -402,9 +379,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "ApiKey"
                 ],
-                "summary": "Update product by ID",
+                "summary": "Update API key by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -414,18 +391,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "API key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Product model",
-                        "name": "product",
+                        "description": "API key model",
+                        "name": "apikey",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductInputDTO"
+                            "$ref": "#/definitions/dto.ApiKeyInputDTO"
                         }
                     }
                 ],
@@ -433,7 +410,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProductOutputDTO"
+                            "$ref": "#/definitions/dto.ApiKeyOutputDTO"
                         }
                     },
                     "400": {
@@ -442,6 +419,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -462,7 +445,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete product by ID",
+                "description": "Delete API key by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -470,9 +453,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "ApiKey"
                 ],
-                "summary": "Delete product by ID",
+                "summary": "Delete API key by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -482,7 +465,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "API key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -492,6 +475,12 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -507,14 +496,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile": {
+        "/audit": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Get profiles",
+                "description": "Get the records of the mutations and logins, the masked columns only show whether they changed",
                 "consumes": [
                     "application/json"
                 ],
@@ -522,9 +514,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Audit"
                 ],
-                "summary": "Get profiles",
+                "summary": "Get audit logs",
                 "parameters": [
                     {
                         "type": "string",
@@ -533,15 +525,2135 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "example": 10,
-                        "name": "limit",
+                        "type": "boolean",
+                        "example": true,
+                        "name": "count",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "descending order 'desc' or ascending order 'asc'",
-                        "name": "order",
+                        "example": "empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "descending order 'desc' or ascending order 'asc'",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'like' by default, or 'fulltext' ranked by relevance",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'updated_at', 'created_at', 'name' or some other sortable field of the resource",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "api_key_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "impersonated_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "created_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ListItemsOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "User authenticated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User authenticated",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "User refresh, in the cookie mode the refresh token is read from its cookie and the new tokens are set in the cookies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User refresh",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSRF token of the cookie mode",
+                        "name": "X-CSRF-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "User authentication. In the cookie mode the tokens are set in HttpOnly cookies and only the CSRF token, to send in the 'X-CSRF-Token' header, is returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Credentials model",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens, or only 'twofactortoken' when the second factor is required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes the session of the token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User logout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/all": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes every session of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User logout everywhere",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc": {
+            "get": {
+                "description": "Redirects to the identity provider, which returns to 'OIDC_REDIRECT_URL' with the code and the state",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Single sign-on",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Expiring session",
+                        "name": "expire",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Completes the login with the code returned by the identity provider",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Single sign-on callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State of the login",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens, or only 'twofactortoken' when the second factor is required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes the password of the authenticated user, the new password must follow the password policy. Every session of the user is revoked and the tokens of a new one are returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Passwords model",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Active sessions of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.SessionOutputDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes one of the user sessions by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke user session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/totp": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Enables the TOTP secret after validating a code, the recovery codes are only returned once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Two-factor enable",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "TOTP code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecoveryCodesOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generates a new TOTP secret and its provisioning URI, also accepts the token of a login waiting for the second factor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Two-factor setup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorSetupOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Disables the two-factor authentication, not allowed when the profile requires it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Two-factor disable",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/totp/setup": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generates a new TOTP secret and its provisioning URI, also accepts the token of a login waiting for the second factor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Two-factor setup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorSetupOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/totp/verify": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Completes the login with the TOTP or a recovery code, enrolling the secret when the profile requires it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Two-factor verify",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/product": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "descending order 'desc' or ascending order 'asc'",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'like' by default, or 'fulltext' ranked by relevance",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'updated_at', 'created_at', 'name' or some other sortable field of the resource",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "created_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "updated_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ListItemsOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Insert product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Insert product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Product model",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get product by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Update product by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Update product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product model",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProductOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Delete product by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Delete product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get profiles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get profiles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "descending order 'desc' or ascending order 'asc'",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'like' by default, or 'fulltext' ranked by relevance",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'updated_at', 'created_at', 'name' or some other sortable field of the resource",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')",
+                        "name": "two_factor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "created_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "updated_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations embedded, comma separated: 'permissions' (default)",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ListItemsOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Insert profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Insert profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Profile model",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get profile by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get profile by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations embedded, comma separated: 'permissions' (default)",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Update profile by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile model",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Delete profile by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Delete profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Creates a disabled user in the volunteer profile and mails it the verification link. The answer is the same when the mail is already registered, the sign-ups are limited per IP and per mail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Register"
+                ],
+                "summary": "Volunteer sign-up",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Sign-up model",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/verify": {
+            "post": {
+                "description": "Verifies the mail of the sign-up with the mailed token, the user is enabled unless a coordinator approval is required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Register"
+                ],
+                "summary": "Verify sign-up",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Verification model",
+                        "name": "verify",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Searches the users, profiles and products readable by the caller in full text, ranked by relevance with the matches highlighted in 'headline'",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Global search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "descending order 'desc' or ascending order 'asc'",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'like' by default, or 'fulltext' ranked by relevance",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'updated_at', 'created_at', 'name' or some other sortable field of the resource",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SearchOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "descending order 'desc' or ascending order 'asc'",
+                        "name": "order",
                         "in": "query"
                     },
                     {
@@ -550,6 +2662,12 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "name": "profile_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "name",
@@ -558,9 +2676,69 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "'updated_at', 'created_at', 'name' or some other field of the response object",
+                        "example": "'like' by default, or 'fulltext' ranked by relevance",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "'updated_at', 'created_at', 'name' or some other sortable field of the resource",
                         "name": "sort",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')",
+                        "name": "mail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')",
+                        "name": "new",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "created_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')",
+                        "name": "updated_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations embedded, comma separated: 'profile' (default), 'profile.permissions' (default)",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -573,6 +2751,18 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -585,9 +2775,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Insert profile",
+                "description": "Insert user",
                 "consumes": [
                     "application/json"
                 ],
@@ -595,9 +2788,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "User"
                 ],
-                "summary": "Insert profile",
+                "summary": "Insert user",
                 "parameters": [
                     {
                         "type": "string",
@@ -606,12 +2799,12 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "description": "Profile model",
-                        "name": "profile",
+                        "description": "User model",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileInputDTO"
+                            "$ref": "#/definitions/dto.UserInputDTO"
                         }
                     }
                 ],
@@ -619,8 +2812,76 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileOutputDTO"
+                            "$ref": "#/definitions/dto.UserOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{email}/passw": {
+            "patch": {
+                "description": "Set user password with the one time token mailed by the invitation or the reset, the password must follow the password policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Set user password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password model",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordInputDTO"
                         }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -628,8 +2889,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
@@ -643,14 +2910,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/{id}": {
+        "/user/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Get profile by ID",
+                "description": "Get user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -658,9 +2928,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "User"
                 ],
-                "summary": "Get profile by ID",
+                "summary": "Get user",
                 "parameters": [
                     {
                         "type": "string",
@@ -670,17 +2940,29 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Profile ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relations embedded, comma separated: 'profile' (default), 'profile.permissions' (default)",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileOutputDTO"
+                            "$ref": "#/definitions/dto.UserOutputDTO"
                         }
                     },
                     "400": {
@@ -689,6 +2971,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -707,9 +2995,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Update profile by ID",
+                "description": "Update user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -717,9 +3008,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "User"
                 ],
-                "summary": "Update profile",
+                "summary": "Update user",
                 "parameters": [
                     {
                         "type": "string",
@@ -729,18 +3020,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Profile ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Profile model",
-                        "name": "profile",
+                        "description": "User model",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileInputDTO"
+                            "$ref": "#/definitions/dto.UserInputDTO"
                         }
                     }
                 ],
@@ -748,7 +3039,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileOutputDTO"
+                            "$ref": "#/definitions/dto.UserOutputDTO"
                         }
                     },
                     "400": {
@@ -757,6 +3048,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -775,9 +3072,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Delete profile by ID",
+                "description": "Delete user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -785,9 +3085,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "User"
                 ],
-                "summary": "Delete profile",
+                "summary": "Delete user",
                 "parameters": [
                     {
                         "type": "string",
@@ -797,7 +3097,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Profile ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -807,6 +3107,12 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -822,14 +3128,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
-            "get": {
+        "/user/{id}/approve": {
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Get all users",
+                "description": "Enables the self registered user by ID once its mail is verified",
                 "consumes": [
                     "application/json"
                 ],
@@ -839,7 +3148,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Get users",
+                "summary": "Approve user",
                 "parameters": [
                     {
                         "type": "string",
@@ -849,102 +3158,33 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "example": 10,
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "descending order 'desc' or ascending order 'asc'",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "name": "profile_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "name",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "'updated_at', 'created_at', 'name' or some other field of the response object",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.ListItemsOutputDTO"
-                            }
+                            "$ref": "#/definitions/dto.UserOutputDTO"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Insert user",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "User"
-                ],
-                "summary": "Insert user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Language responses",
-                        "name": "lang",
-                        "in": "query"
                     },
-                    {
-                        "description": "User model",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserInputDTO"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserOutputDTO"
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
@@ -964,9 +3204,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{email}/passw": {
-            "patch": {
-                "description": "Set user password by ID",
+        "/user/{id}/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Issues a short-lived access token of the user by ID for the authenticated admin, whose profile must grant every permission of the user. The token carries the admin in its 'act' claim, it can not change passwords, permissions or credentials and the audit log records the admin as the actor",
                 "consumes": [
                     "application/json"
                 ],
@@ -976,7 +3221,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Set user password",
+                "summary": "Impersonate user",
                 "parameters": [
                     {
                         "type": "string",
@@ -985,25 +3230,31 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "User email",
-                        "name": "email",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Password model",
-                        "name": "password",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PasswordInputDTO"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -1020,14 +3271,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{id}": {
-            "get": {
+        "/user/{id}/reset": {
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Get user by ID",
+                "description": "Reset user password by ID and mail a reset link, users without password receive the invitation again",
                 "consumes": [
                     "application/json"
                 ],
@@ -1037,7 +3291,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Get user",
+                "summary": "Reset user password",
                 "parameters": [
                     {
                         "type": "string",
@@ -1055,13 +3309,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserOutputDTO"
-                        }
+                        "description": "OK"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
@@ -1079,14 +3330,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/user/{id}/sessions": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Update user by ID",
+                "description": "Active sessions of the user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1096,7 +3352,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Update user",
+                "summary": "Get user sessions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1110,22 +3366,16 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "User model",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserInputDTO"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserOutputDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.SessionOutputDTO"
+                            }
                         }
                     },
                     "400": {
@@ -1134,6 +3384,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httphelper.HTTPResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1152,9 +3408,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Delete user by ID",
+                "description": "Revokes every session of the user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1164,7 +3423,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Delete user",
+                "summary": "Revoke user sessions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1184,6 +3443,18 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1199,14 +3470,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{id}/reset": {
+        "/user/{id}/unlock": {
             "patch": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Reset user password by ID",
+                "description": "Clears the failed login attempts of the user by ID, lifting the lockout",
                 "consumes": [
                     "application/json"
                 ],
@@ -1216,7 +3490,7 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Reset user password",
+                "summary": "Unlock user",
                 "parameters": [
                     {
                         "type": "string",
@@ -1233,8 +3507,20 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -1253,6 +3539,87 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "dto.ApiKeyInputDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "River gauge 01"
+                },
+                "profile_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "product:read",
+                        "product:create"
+                    ]
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.ApiKeyOutputDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-03-01T08:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "type": "string",
+                    "example": "msd_Xk3vQ9aBzT0pL8mN2wR5yC7eH1jK4uV6sD9fG3hJ0qW"
+                },
+                "last_used": {
+                    "type": "string",
+                    "example": "2026-03-01T09:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "River gauge 01"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "msd_Xk3vQ9aB"
+                },
+                "profile": {
+                    "$ref": "#/definitions/dto.ProfileOutputDTO"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "product:read",
+                        "product:create"
+                    ]
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "dto.AuthInputDTO": {
             "type": "object",
             "properties": {
@@ -1276,70 +3643,132 @@ const docTemplate = `{
                 "accesstoken": {
                     "type": "string"
                 },
+                "csrftoken": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "refreshtoken": {
                     "type": "string"
                 },
+                "twofactorsetup": {
+                    "type": "boolean"
+                },
+                "twofactortoken": {
+                    "type": "string"
+                },
                 "user": {
                     "$ref": "#/definitions/dto.UserOutputDTO"
                 }
             }
         },
-        "dto.ListItemsOutputDTO": {
+        "dto.ChangePasswordInputDTO": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
+                "current_password": {
+                    "type": "string",
+                    "example": "river7stone"
                 },
-                "items": {}
-            }
-        },
-        "dto.PasswordInputDTO": {
-            "type": "object",
-            "properties": {
                 "password": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "lake9pebble"
                 },
                 "password_confirm": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "lake9pebble"
                 }
             }
         },
-        "dto.PermissionsInputDTO": {
+        "dto.GrantInputDTO": {
             "type": "object",
             "properties": {
-                "product_module": {
+                "create": {
                     "type": "boolean",
                     "example": true
                 },
-                "profile_module": {
+                "delete": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "read": {
                     "type": "boolean",
                     "example": true
                 },
-                "user_module": {
+                "update": {
                     "type": "boolean",
                     "example": true
                 }
             }
         },
-        "dto.PermissionsOutputDTO": {
+        "dto.GrantOutputDTO": {
             "type": "object",
             "properties": {
-                "product_module": {
+                "create": {
                     "type": "boolean",
                     "example": true
                 },
-                "profile_module": {
+                "delete": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "read": {
                     "type": "boolean",
                     "example": true
                 },
-                "user_module": {
+                "update": {
                     "type": "boolean",
                     "example": true
                 }
             }
         },
+        "dto.ListItemsOutputDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {},
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PasswordInputDTO": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "river7stone"
+                },
+                "password_confirm": {
+                    "type": "string",
+                    "example": "river7stone"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "Qm9hcmQgb2YgZGlyZWN0b3JzIGFwcHJvdmVk"
+                }
+            }
+        },
+        "dto.PermissionsInputDTO": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/dto.GrantInputDTO"
+            }
+        },
+        "dto.PermissionsOutputDTO": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/dto.GrantOutputDTO"
+            }
+        },
         "dto.ProductInputDTO": {
             "type": "object",
             "properties": {
@@ -1352,6 +3781,10 @@ const docTemplate = `{
         "dto.ProductOutputDTO": {
             "type": "object",
             "properties": {
+                "headline": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eProduct\u003c/mark\u003e 01"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -1371,12 +3804,20 @@ const docTemplate = `{
                 },
                 "permissions": {
                     "$ref": "#/definitions/dto.PermissionsInputDTO"
+                },
+                "two_factor": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
         "dto.ProfileOutputDTO": {
             "type": "object",
             "properties": {
+                "headline": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eADMIN\u003c/mark\u003e"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -1387,6 +3828,119 @@ const docTemplate = `{
                 },
                 "permissions": {
                     "$ref": "#/definitions/dto.PermissionsOutputDTO"
+                },
+                "two_factor": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "dto.RecoveryCodesOutputDTO": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "a1b2c-3d4e5",
+                        "f6g7h-i8j9k"
+                    ]
+                }
+            }
+        },
+        "dto.RegisterInputDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "amina.wanjiru@email.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Amina Wanjiru"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "river7stone"
+                },
+                "password_confirm": {
+                    "type": "string",
+                    "example": "river7stone"
+                }
+            }
+        },
+        "dto.SearchOutputDTO": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "$ref": "#/definitions/dto.ListItemsOutputDTO"
+                },
+                "profiles": {
+                    "$ref": "#/definitions/dto.ListItemsOutputDTO"
+                },
+                "users": {
+                    "$ref": "#/definitions/dto.ListItemsOutputDTO"
+                }
+            }
+        },
+        "dto.SessionOutputDTO": {
+            "type": "object",
+            "properties": {
+                "agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (Linux; Android 13)"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-01T08:00:00Z"
+                },
+                "current": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "impersonator_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "last_seen": {
+                    "type": "string",
+                    "example": "2024-03-01T09:30:00Z"
+                }
+            }
+        },
+        "dto.TwoFactorInputDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "recovery_code": {
+                    "type": "string",
+                    "example": "a1b2c-3d4e5"
+                }
+            }
+        },
+        "dto.TwoFactorSetupOutputDTO": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                },
+                "uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/MSAADA:john.cena@email.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=MSAADA"
                 }
             }
         },
@@ -1418,10 +3972,18 @@ const docTemplate = `{
                     "type": "string",
                     "example": "john.cena@email.com"
                 },
+                "headline": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eJohn\u003c/mark\u003e Cena john.cena@email.com"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "impersonator_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "name": {
                     "type": "string",
                     "example": "John Cena"
@@ -1432,6 +3994,23 @@ const docTemplate = `{
                 "status": {
                     "type": "boolean",
                     "example": true
+                },
+                "verified": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.VerifyInputDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "amina.wanjiru@email.com"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "Qm9hcmQgb2YgZGlyZWN0b3JzIGFwcHJvdmVk"
                 }
             }
         },
@@ -1447,9 +4026,55 @@ const docTemplate = `{
                     "example": "status bad request"
                 }
             }
+        },
+        "keyring.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "RS256"
+                },
+                "e": {
+                    "type": "string",
+                    "example": "AQAB"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "type": "string",
+                    "example": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                }
+            }
+        },
+        "keyring.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/keyring.JWK"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "ApiKey": {
+            "description": "Type \"ApiKey\" followed by a space and the API key.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
         "Bearer": {
             "description": "Type \"Bearer\" followed by a space and JWT token.",
             "type": "apiKey",
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/pgerror"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/validator"
+)
+
+type ApiKeyHandler struct {
+	apiKeyService domain.ApiKeyService
+}
+
+func (h *ApiKeyHandler) handlerError(c *fiber.Ctx, err error) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	switch err := pgerror.HandlerError(err); {
+	case errors.Is(err, domain.ErrApiKeyForbidden):
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, translation.ErrApiKeyForbidden)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrProfileNotFound)
+	case errors.Is(err, domain.ErrInvalidScope):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidScope)
+	case errors.Is(err, pgerror.ErrForeignKeyViolated):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrProfileNotFound)
+	case errors.Is(err, pgerror.ErrUndefinedColumn):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUndefinedColumn)
+	case errors.Is(err, filter.ErrInvalidSort):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidSort)
+	case errors.Is(err, filter.ErrInvalidCursor):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidCursor)
+	}
+
+	if errors.As(err, &validator.ErrValidator) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, err)
+	}
+
+	log.Println(err.Error())
+	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
+}
+
+// NewApiKeyHandler Creates a new API key handler, the keys are only managed by users.
+func NewApiKeyHandler(route fiber.Router, as domain.ApiKeyService, mid *middleware.RequesttMiddleware) {
+	handler := &ApiKeyHandler{
+		apiKeyService: as,
+	}
+
+	route.Use(middleware.MidAccess, middleware.MidRateLimit, middleware.DenyImpersonation, middleware.CheckPermission(domain.ModuleApiKey))
+
+	route.Get("", middleware.GetFieldset(domain.ApiKeyRelations), middleware.GetFilter(domain.ApiKeyFilterFields), handler.getApiKeys)
+	route.Post("", middleware.GetApiKeyDTO, handler.createApiKey)
+	route.Get("/:"+httphelper.ParamID, middleware.GetFieldset(domain.ApiKeyRelations), mid.ApiKeyByID, handler.getApiKeyBydID)
+	route.Put("/:"+httphelper.ParamID, mid.ApiKeyByID, middleware.GetApiKeyDTO, handler.updateApiKey)
+	route.Delete("/:"+httphelper.ParamID, mid.ApiKeyByID, handler.deleteApiKey)
+}
+
+// getApiKeys godoc
+// @Summary      Get API keys
+// @Description  Get API keys
+// @Tags         ApiKey
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        filter query filter.Filter false "Optional Filter"
+// @Param        id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        name query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        prefix query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        status query string false "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')"
+// @Param        profile_id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        expires_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        last_used query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        created_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        updated_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Param        include query string false "Relations embedded, comma separated: 'profile' (default)"
+// @Success      200  {array}   dto.ListItemsOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /apikey [get]
+// @Security	 Bearer
+func (h *ApiKeyHandler) getApiKeys(c *fiber.Ctx) error {
+	response, err := h.apiKeyService.GetApiKeys(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.Filter))
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// getApiKeyBydID godoc
+// @Summary      Get API key by ID
+// @Description  Get API key by ID
+// @Tags         ApiKey
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id   path			int			true        "API key ID"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Success      200  {object}  dto.ApiKeyOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /apikey/{id} [get]
+// @Security	 Bearer
+func (h *ApiKeyHandler) getApiKeyBydID(c *fiber.Ctx) error {
+	apiKey := c.Locals(httphelper.LocalObject).(*domain.ApiKey)
+	response, err := h.apiKeyService.GetApiKeyByID(c.Context(), apiKey.Id)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// createApiKey godoc
+// @Summary      Insert API key
+// @Description  Insert API key, the generated key is only returned in this response
+// @Tags         ApiKey
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        apikey body dto.ApiKeyInputDTO true "API key model"
+// @Success      201  {object}  dto.ApiKeyOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /apikey [post]
+// @Security	 Bearer
+func (h *ApiKeyHandler) createApiKey(c *fiber.Ctx) error {
+	apiKeyDTO := c.Locals(httphelper.LocalDTO).(*dto.ApiKeyInputDTO)
+	caller, _ := c.Locals(httphelper.LocalUser).(*domain.User)
+	apiKey, err := h.apiKeyService.CreateApiKey(c.Context(), caller, apiKeyDTO)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(apiKey)
+}
+
+// updateApiKey godoc
+// @Summary      Update API key by ID
+// @Description  Update API key by ID
+// @Tags         ApiKey
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "API key ID"
+// @Param        apikey body dto.ApiKeyInputDTO true "API key model"
+// @Success      200  {object}  dto.ApiKeyOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /apikey/{id} [put]
+// @Security	 Bearer
+func (h *ApiKeyHandler) updateApiKey(c *fiber.Ctx) error {
+	apiKeyDTO := c.Locals(httphelper.LocalDTO).(*dto.ApiKeyInputDTO)
+	oldApiKey := c.Locals(httphelper.LocalObject).(*domain.ApiKey)
+	caller, _ := c.Locals(httphelper.LocalUser).(*domain.User)
+	newApiKey, err := h.apiKeyService.UpdateApiKey(c.Context(), caller, oldApiKey, apiKeyDTO)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newApiKey)
+}
+
+// deleteApiKey godoc
+// @Summary      Delete API key by ID
+// @Description  Delete API key by ID
+// @Tags         ApiKey
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "API key ID"
+// @Success      204  {object}  nil
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /apikey/{id} [delete]
+// @Security	 Bearer
+func (h *ApiKeyHandler) deleteApiKey(c *fiber.Ctx) error {
+	apiKey := c.Locals(httphelper.LocalObject).(*domain.ApiKey)
+	if err := h.apiKeyService.DeleteApiKey(c.Context(), apiKey); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
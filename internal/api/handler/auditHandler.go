@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/pgerror"
+)
+
+type AuditHandler struct {
+	auditService domain.AuditService
+}
+
+func (h *AuditHandler) handlerError(c *fiber.Ctx, err error) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	switch err := pgerror.HandlerError(err); {
+	case errors.Is(err, pgerror.ErrUndefinedColumn):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUndefinedColumn)
+	case errors.Is(err, filter.ErrInvalidSort):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidSort)
+	case errors.Is(err, filter.ErrInvalidCursor):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidCursor)
+	}
+
+	log.Println(err.Error())
+	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
+}
+
+// NewAuditHandler Creates a new audit handler, the logs are only read.
+func NewAuditHandler(route fiber.Router, as domain.AuditService) {
+	handler := &AuditHandler{
+		auditService: as,
+	}
+
+	route.Use(middleware.MidResource, middleware.MidRateLimit, middleware.CheckPermission(domain.ModuleAudit))
+
+	route.Get("", middleware.GetFieldset(nil), middleware.GetAuditFilter, handler.getAuditLogs)
+}
+
+// getAuditLogs godoc
+// @Summary      Get audit logs
+// @Description  Get the records of the mutations and logins, the masked columns only show whether they changed
+// @Tags         Audit
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        filter query filter.Filter false "Optional Filter"
+// @Param        id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        actor_id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        api_key_id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        impersonated_id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        action query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        entity query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        entity_id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        request_id query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        ip query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        created_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Success      200  {array}   dto.ListItemsOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /audit [get]
+// @Security	 Bearer
+// @Security	 ApiKey
+func (h *AuditHandler) getAuditLogs(c *fiber.Ctx) error {
+	response, err := h.auditService.GetAuditLogs(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.Filter))
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
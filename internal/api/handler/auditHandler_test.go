@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+)
+
+// auditor Profile creating products and granted every action on the audit logs.
+func auditor() *domain.Profile {
+	return &domain.Profile{
+		Base: domain.Base{Id: 3},
+		Name: "AUDITOR",
+		Permissions: []domain.Permission{
+			{Module: domain.ModuleProduct, Read: true, Create: true},
+			{Module: domain.ModuleAudit, Read: true, Create: true, Update: true, Delete: true},
+		},
+	}
+}
+
+// go test -run TestAuditAppendOnly
+func TestAuditAppendOnly(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.addUser(t, "auditor", auditor())
+	token := bearer(env.login(t, user).AccessToken)
+
+	name := "Water filters"
+	resp := env.request(t, fiber.MethodPost, "/product", &dto.ProductInputDTO{Name: &name}, fiber.HeaderAuthorization, token)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	// The login and the creation are recorded, the latter by the user of the request.
+	assert.Len(t, env.audits.logs, 2)
+	created := env.audits.logs[1]
+	assert.Equal(t, domain.AuditCreate, created.Action)
+	assert.Equal(t, domain.ProductTableName, created.Entity)
+	assert.Equal(t, uint(1), *created.EntityID)
+	assert.Equal(t, user.Id, *created.ActorID)
+	assert.Nil(t, created.ApiKeyID)
+	assert.Equal(t, domain.AuditChange{After: name}, created.Diff["name"])
+
+	// Not even a profile granted every action on the logs can change them, no route does.
+	logs := slices.Clone(env.audits.logs)
+	for _, path := range []string{"/audit", "/audit/1"} {
+		for _, method := range []string{fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete} {
+			resp := env.request(t, method, path, &dto.ProductInputDTO{Name: &name}, fiber.HeaderAuthorization, token)
+			assert.Contains(t, []int{fiber.StatusNotFound, fiber.StatusMethodNotAllowed}, resp.StatusCode, method+" "+path)
+		}
+	}
+	assert.Equal(t, logs, env.audits.logs)
+
+	resp = env.request(t, fiber.MethodGet, "/audit", nil, fiber.HeaderAuthorization, token)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	list := &struct {
+		Items []dto.AuditLogOutputDTO `json:"items"`
+	}{}
+	decode(t, resp, list)
+	assert.Len(t, list.Items, 2)
+
+	// A new mutation only appends its row.
+	resp = env.request(t, fiber.MethodPost, "/product", &dto.ProductInputDTO{Name: &name}, fiber.HeaderAuthorization, token)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Len(t, env.audits.logs, 3)
+	assert.Equal(t, logs, env.audits.logs[:2])
+}
+
+// go test -run TestAuditApiKey
+func TestAuditApiKey(t *testing.T) {
+	env := newTestEnv(t)
+
+	apiKey := &domain.ApiKey{Base: domain.Base{Id: 7}, Profile: auditor(), Scopes: []string{"product:*"}, Status: true}
+	raw, err := apiKey.GenerateApiKey()
+	assert.Nil(t, err)
+	env.apiKeys.apiKeys[apiKey.Hash] = apiKey
+
+	name := "Water filters"
+	resp := env.request(t, fiber.MethodPost, "/product", &dto.ProductInputDTO{Name: &name}, fiber.HeaderAuthorization, domain.ApiKeyScheme+" "+raw)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	// The mutations of a machine are recorded by its key, without a user.
+	assert.Len(t, env.audits.logs, 1)
+	assert.Equal(t, apiKey.Id, *env.audits.logs[0].ApiKeyID)
+	assert.Nil(t, env.audits.logs[0].ActorID)
+
+	// Its scopes do not include the audit logs.
+	resp = env.request(t, fiber.MethodGet, "/audit", nil, fiber.HeaderAuthorization, domain.ApiKeyScheme+" "+raw)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
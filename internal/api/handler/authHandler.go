@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -12,10 +14,12 @@ import (
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
 	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/oidc"
 )
 
 type AuthHandler struct {
-	authService domain.AuthService
+	authService    domain.AuthService
+	sessionService domain.SessionService
 }
 
 func (s *AuthHandler) handlerError(c *fiber.Ctx, err error) error {
@@ -29,6 +33,53 @@ func (s *AuthHandler) handlerError(c *fiber.Ctx, err error) error {
 		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidIpAssociation)
 	}
 
+	if errors.Is(err, domain.ErrTokenReused) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrTokenReused)
+	}
+
+	if errors.Is(err, domain.ErrInvalidSession) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidSession)
+	}
+
+	var locked *domain.LockedError
+	if errors.As(err, &locked) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(locked.Until).Seconds())+1))
+		return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrLoginLocked)
+	}
+
+	if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidTwoFactorCode)
+	}
+
+	if errors.Is(err, domain.ErrTwoFactorEnabled) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusConflict, messages.ErrTwoFactorEnabled)
+	}
+
+	if errors.Is(err, domain.ErrTwoFactorRequired) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrTwoFactorRequired)
+	}
+
+	if message := passwordPolicyError(messages, err); message != nil {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, message)
+	}
+
+	if errors.Is(err, domain.ErrOidcDisabled) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, messages.ErrOidcDisabled)
+	}
+
+	if errors.Is(err, domain.ErrOidcEmailNotVerified) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrOidcEmailNotVerified)
+	}
+
+	if errors.Is(err, domain.ErrInvalidOidcState) || errors.Is(err, oidc.ErrExchange) || errors.Is(err, oidc.ErrInvalidToken) || errors.Is(err, oidc.ErrInvalidNonce) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrOidcLogin)
+	}
+
+	if errors.Is(err, oidc.ErrDiscovery) {
+		log.Println(err.Error())
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadGateway, messages.ErrOidcLogin)
+	}
+
 	switch err.Error() {
 	case "invalid password":
 		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrIncorrectPassword)
@@ -40,27 +91,61 @@ func (s *AuthHandler) handlerError(c *fiber.Ctx, err error) error {
 	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, messages.ErrGeneric)
 }
 
+// tokenResponse Sends the tokens, in the cookies instead of the body when the cookie mode is enabled.
+func (s *AuthHandler) tokenResponse(c *fiber.Ctx, response *dto.AuthOutputDTO) error {
+	if middleware.AuthCookies != nil {
+		if err := middleware.AuthCookies.Set(c, response); err != nil {
+			return s.handlerError(c, err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// clearCookies Removes the cookies of the tokens once the session of the request is revoked.
+func (s *AuthHandler) clearCookies(c *fiber.Ctx) {
+	if middleware.AuthCookies != nil {
+		middleware.AuthCookies.Clear(c)
+	}
+}
+
 // NewAuthHandler Creates a new authenticator handler.
-func NewAuthHandler(route fiber.Router, as domain.AuthService) {
+func NewAuthHandler(route fiber.Router, as domain.AuthService, ss domain.SessionService) {
 	handler := &AuthHandler{
-		authService: as,
+		authService:    as,
+		sessionService: ss,
 	}
 
-	route.Post("", handler.login)
+	route.Post("", middleware.MidAuthRateLimit, handler.login)
 	route.Get("", middleware.MidAccess, handler.me)
 	route.Put("", middleware.MidRefresh, handler.refresh)
+	route.Delete("", middleware.MidAccess, handler.logout)
+	route.Delete("/all", middleware.MidAccess, handler.logoutAll)
+	route.Patch("/password", middleware.MidAuthRateLimit, middleware.MidAccess, middleware.DenyImpersonation, middleware.GetChangePasswordDTO, handler.changePassword)
+	route.Get("/sessions", middleware.MidAccess, handler.getSessions)
+	route.Delete("/sessions/:"+httphelper.ParamID, middleware.MidAccess, handler.revokeSession)
+
+	route.Post("/totp", middleware.MidAccess, middleware.DenyImpersonation, handler.setupTwoFactor)
+	route.Put("/totp", middleware.MidAccess, middleware.DenyImpersonation, middleware.GetTwoFactorDTO, handler.enableTwoFactor)
+	route.Delete("/totp", middleware.MidAccess, middleware.DenyImpersonation, middleware.GetTwoFactorDTO, handler.disableTwoFactor)
+	route.Post("/totp/setup", middleware.MidTwoFactor, handler.setupTwoFactor)
+	route.Post("/totp/verify", middleware.MidAuthRateLimit, middleware.MidTwoFactor, middleware.GetTwoFactorDTO, handler.verifyTwoFactor)
+
+	route.Get("/oidc", middleware.MidAuthRateLimit, handler.oidcLogin)
+	route.Get("/oidc/callback", middleware.MidAuthRateLimit, handler.oidcCallback)
 }
 
 // login godoc
 // @Summary      User authentication
-// @Description  User authentication
+// @Description  User authentication. In the cookie mode the tokens are set in HttpOnly cookies and only the CSRF token, to send in the 'X-CSRF-Token' header, is returned
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        credentials body dto.AuthInputDTO true "Credentials model"
-// @Success      200  {object}  dto.AuthOutputDTO
+// @Success      200  {object}  dto.AuthOutputDTO "Tokens, or only 'twofactortoken' when the second factor is required"
 // @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      429  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /auth [post]
 func (s *AuthHandler) login(c *fiber.Ctx) error {
@@ -70,12 +155,12 @@ func (s *AuthHandler) login(c *fiber.Ctx) error {
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidDatas)
 	}
 
-	authResponse, err := s.authService.Login(c.Context(), credentials, c.IP())
+	authResponse, err := s.authService.Login(c.Context(), credentials, c.IP(), c.Get(fiber.HeaderUserAgent))
 	if err != nil {
 		return s.handlerError(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(authResponse)
+	return s.tokenResponse(c, authResponse)
 }
 
 // me godoc
@@ -92,23 +177,319 @@ func (s *AuthHandler) login(c *fiber.Ctx) error {
 // @Router       /auth [get]
 // @Security	 Bearer
 func (s *AuthHandler) me(c *fiber.Ctx) error {
-	user := c.Locals(httphelper.LocalUser).(*domain.User)
-	return c.Status(fiber.StatusOK).JSON(s.authService.Me(user))
+	user := s.authService.Me(c.Locals(httphelper.LocalUser).(*domain.User))
+	if impersonator, ok := c.Locals(httphelper.LocalImpersonator).(uint); ok {
+		user.ImpersonatorID = &impersonator
+	}
+
+	return c.Status(fiber.StatusOK).JSON(user)
 }
 
 // refresh godoc
 // @Summary      User refresh
-// @Description  User refresh
+// @Description  User refresh, in the cookie mode the refresh token is read from its cookie and the new tokens are set in the cookies
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        Authorization header string false "User token"
+// @Param        X-CSRF-Token header string false "CSRF token of the cookie mode"
 // @Param        lang query string false "Language responses"
 // @Success      200  {object}  dto.AuthOutputDTO
 // @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /auth [put]
 func (s *AuthHandler) refresh(c *fiber.Ctx) error {
+	session := c.Locals(httphelper.LocalSession).(*domain.Session)
+	authResponse, err := s.authService.Refresh(c.Context(), session, c.IP())
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return s.tokenResponse(c, authResponse)
+}
+
+// changePassword godoc
+// @Summary      Change password
+// @Description  Changes the password of the authenticated user, the new password must follow the password policy. Every session of the user is revoked and the tokens of a new one are returned
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        password body dto.ChangePasswordInputDTO true "Passwords model"
+// @Success      200  {object}  dto.AuthOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      429  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/password [patch]
+// @Security	 Bearer
+func (s *AuthHandler) changePassword(c *fiber.Ctx) error {
+	data := c.Locals(httphelper.LocalDTO).(*dto.ChangePasswordInputDTO)
+	if !data.IsValid() {
+		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrPassUnmatch)
+	}
+
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	session := c.Locals(httphelper.LocalSession).(*domain.Session)
+	authResponse, err := s.authService.ChangePassword(c.Context(), user, session, data, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return s.tokenResponse(c, authResponse)
+}
+
+// logout godoc
+// @Summary      User logout
+// @Description  Revokes the session of the token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Success      204  {object}  nil
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth [delete]
+// @Security	 Bearer
+func (s *AuthHandler) logout(c *fiber.Ctx) error {
+	session := c.Locals(httphelper.LocalSession).(*domain.Session)
+	if err := s.sessionService.RevokeSession(c.Context(), session); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	s.clearCookies(c)
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// logoutAll godoc
+// @Summary      User logout everywhere
+// @Description  Revokes every session of the user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Success      204  {object}  nil
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/all [delete]
+// @Security	 Bearer
+func (s *AuthHandler) logoutAll(c *fiber.Ctx) error {
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	if err := s.sessionService.RevokeUserSessions(c.Context(), user); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	s.clearCookies(c)
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// getSessions godoc
+// @Summary      User sessions
+// @Description  Active sessions of the user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Success      200  {array}   dto.SessionOutputDTO
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/sessions [get]
+// @Security	 Bearer
+func (s *AuthHandler) getSessions(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalUser).(*domain.User)
-	return c.Status(fiber.StatusOK).JSON(s.authService.Refresh(user, c.IP()))
+	session := c.Locals(httphelper.LocalSession).(*domain.Session)
+
+	sessions, err := s.sessionService.GetUserSessions(c.Context(), user, session)
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sessions)
+}
+
+// revokeSession godoc
+// @Summary      Revoke user session
+// @Description  Revokes one of the user sessions by ID
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "Session ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/sessions/{id} [delete]
+// @Security	 Bearer
+func (s *AuthHandler) revokeSession(c *fiber.Ctx) error {
+	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	id, err := c.ParamsInt(httphelper.ParamID, 0)
+	if err != nil || id < 1 {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidId)
+	}
+
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	if err := s.sessionService.RevokeUserSession(c.Context(), user, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, messages.ErrSessionNotFound)
+		}
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// setupTwoFactor godoc
+// @Summary      Two-factor setup
+// @Description  Generates a new TOTP secret and its provisioning URI, also accepts the token of a login waiting for the second factor
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Success      200  {object}  dto.TwoFactorSetupOutputDTO
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      409  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/totp [post]
+// @Router       /auth/totp/setup [post]
+// @Security	 Bearer
+func (s *AuthHandler) setupTwoFactor(c *fiber.Ctx) error {
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	setup, err := s.authService.SetupTwoFactor(c.Context(), user)
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(setup)
+}
+
+// enableTwoFactor godoc
+// @Summary      Two-factor enable
+// @Description  Enables the TOTP secret after validating a code, the recovery codes are only returned once
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        code body dto.TwoFactorInputDTO true "TOTP code"
+// @Success      200  {object}  dto.RecoveryCodesOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      409  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/totp [put]
+// @Security	 Bearer
+func (s *AuthHandler) enableTwoFactor(c *fiber.Ctx) error {
+	input := c.Locals(httphelper.LocalDTO).(*dto.TwoFactorInputDTO)
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	codes, err := s.authService.EnableTwoFactor(c.Context(), user, input)
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(codes)
+}
+
+// disableTwoFactor godoc
+// @Summary      Two-factor disable
+// @Description  Disables the two-factor authentication, not allowed when the profile requires it
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        code body dto.TwoFactorInputDTO true "TOTP or recovery code"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/totp [delete]
+// @Security	 Bearer
+func (s *AuthHandler) disableTwoFactor(c *fiber.Ctx) error {
+	input := c.Locals(httphelper.LocalDTO).(*dto.TwoFactorInputDTO)
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	if err := s.authService.DisableTwoFactor(c.Context(), user, input); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// verifyTwoFactor godoc
+// @Summary      Two-factor verify
+// @Description  Completes the login with the TOTP or a recovery code, enrolling the secret when the profile requires it
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        code body dto.TwoFactorInputDTO true "TOTP or recovery code"
+// @Success      200  {object}  dto.AuthOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      429  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /auth/totp/verify [post]
+// @Security	 Bearer
+func (s *AuthHandler) verifyTwoFactor(c *fiber.Ctx) error {
+	input := c.Locals(httphelper.LocalDTO).(*dto.TwoFactorInputDTO)
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	claims := c.Locals(httphelper.LocalClaims).(*domain.Claims)
+	authResponse, err := s.authService.VerifyTwoFactor(c.Context(), user, claims, input, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return s.tokenResponse(c, authResponse)
+}
+
+// oidcLogin godoc
+// @Summary      Single sign-on
+// @Description  Redirects to the identity provider, which returns to 'OIDC_REDIRECT_URL' with the code and the state
+// @Tags         Auth
+// @Param        lang query string false "Language responses"
+// @Param        expire query bool false "Expiring session"
+// @Success      302
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      502  {object}  httphelper.HTTPResponse
+// @Router       /auth/oidc [get]
+func (s *AuthHandler) oidcLogin(c *fiber.Ctx) error {
+	address, err := s.authService.OidcLogin(c.Context(), c.QueryBool("expire"))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Redirect(address, fiber.StatusFound)
+}
+
+// oidcCallback godoc
+// @Summary      Single sign-on callback
+// @Description  Completes the login with the code returned by the identity provider
+// @Tags         Auth
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        code query string true "Authorization code"
+// @Param        state query string true "State of the login"
+// @Success      200  {object}  dto.AuthOutputDTO "Tokens, or only 'twofactortoken' when the second factor is required"
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      502  {object}  httphelper.HTTPResponse
+// @Router       /auth/oidc/callback [get]
+func (s *AuthHandler) oidcCallback(c *fiber.Ctx) error {
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrOidcLogin)
+	}
+
+	authResponse, err := s.authService.OidcCallback(c.Context(), code, state, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return s.tokenResponse(c, authResponse)
 }
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+// go test -run TestRefreshRotation
+func TestRefreshRotation(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+	auth := env.login(t, user)
+
+	resp := env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderAuthorization, bearer(auth.RefreshToken))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	rotated := &dto.AuthOutputDTO{}
+	decode(t, resp, rotated)
+	assert.NotEqual(t, auth.RefreshToken, rotated.RefreshToken)
+	assert.Equal(t, user.Id, rotated.User.Id)
+
+	// The rotated tokens belong to the same session.
+	resp = env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderAuthorization, bearer(rotated.AccessToken))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Len(t, env.sessions.sessions, 1)
+
+	resp = env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderAuthorization, bearer(rotated.RefreshToken))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// An access token is not accepted in place of the refresh one.
+	resp = env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderAuthorization, bearer(auth.AccessToken))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// go test -run TestRefreshReuse
+func TestRefreshReuse(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+	auth := env.login(t, user)
+
+	resp := env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderAuthorization, bearer(auth.RefreshToken))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	rotated := &dto.AuthOutputDTO{}
+	decode(t, resp, rotated)
+
+	// The refresh token of a past rotation was stolen or replayed, the whole session is revoked.
+	resp = env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderAuthorization, bearer(auth.RefreshToken))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	body := &httphelper.HTTPResponse{}
+	decode(t, resp, body)
+	assert.Equal(t, env.messages.ErrTokenReused.Error(), body.Message)
+	assert.NotNil(t, env.sessions.sessions[0].RevokedAt)
+
+	resp = env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderAuthorization, bearer(rotated.RefreshToken))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderAuthorization, bearer(rotated.AccessToken))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	// The other sessions of the user are kept.
+	other := env.login(t, user)
+	resp = env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderAuthorization, bearer(other.AccessToken))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+// go test -run TestLoginLockout
+func TestLoginLockout(t *testing.T) {
+	t.Setenv("LOGIN_MAX_ATTEMPTS", "3")
+	env := newTestEnv(t)
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+	wrong := &dto.AuthInputDTO{Login: user.Email, Password: "wrong password"}
+
+	for i := 0; i < 3; i++ {
+		resp := env.request(t, fiber.MethodPost, "/auth", wrong)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	}
+
+	// From the limit on even the right password is refused until the lock ends.
+	resp := env.request(t, fiber.MethodPost, "/auth", &dto.AuthInputDTO{Login: user.Email, Password: testPassword})
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	// The lock is kept to the account.
+	other := env.addUser(t, "coordinator", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+	env.login(t, other)
+}
+
+// go test -run TestLoginLockoutReset
+func TestLoginLockoutReset(t *testing.T) {
+	t.Setenv("LOGIN_MAX_ATTEMPTS", "3")
+	env := newTestEnv(t)
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+	wrong := &dto.AuthInputDTO{Login: user.Email, Password: "wrong password"}
+
+	// A successful login starts the count of the account again.
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, fiber.StatusUnauthorized, env.request(t, fiber.MethodPost, "/auth", wrong).StatusCode)
+	}
+	env.login(t, user)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, fiber.StatusUnauthorized, env.request(t, fiber.MethodPost, "/auth", wrong).StatusCode)
+	}
+	env.login(t, user)
+}
+
+// go test -run TestLoginLockoutAddress
+func TestLoginLockoutAddress(t *testing.T) {
+	t.Setenv("LOGIN_IP_MAX_ATTEMPTS", "4")
+	env := newTestEnv(t)
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+
+	// Guessing across accounts, known or not, is counted for the address.
+	for _, mail := range []string{"a@msaada.org", "b@msaada.org", user.Email, "c@msaada.org"} {
+		resp := env.request(t, fiber.MethodPost, "/auth", &dto.AuthInputDTO{Login: mail, Password: "wrong password"})
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	}
+
+	resp := env.request(t, fiber.MethodPost, "/auth", &dto.AuthInputDTO{Login: user.Email, Password: testPassword})
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+}
+
+// cookieHeader Returns the 'Cookie' header sending back the cookies of the names.
+func cookieHeader(cookies []*http.Cookie, names ...string) string {
+	pairs := []string{}
+	for _, cookie := range cookies {
+		if slices.Contains(names, cookie.Name) {
+			pairs = append(pairs, cookie.Name+"="+cookie.Value)
+		}
+	}
+
+	return strings.Join(pairs, "; ")
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+// go test -run TestCookieMode
+func TestCookieMode(t *testing.T) {
+	env := newCookieTestEnv(t, &middleware.CookieConfig{SameSite: fiber.CookieSameSiteStrictMode})
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+
+	resp := env.request(t, fiber.MethodPost, "/auth", &dto.AuthInputDTO{Login: user.Email, Password: testPassword})
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// The tokens are only kept by the browser, the dashboard reads the CSRF token.
+	auth := &dto.AuthOutputDTO{}
+	decode(t, resp, auth)
+	assert.Empty(t, auth.AccessToken)
+	assert.Empty(t, auth.RefreshToken)
+	assert.NotEmpty(t, auth.CsrfToken)
+
+	cookies := resp.Cookies()
+	assert.True(t, findCookie(cookies, middleware.CookieAccess).HttpOnly)
+	assert.True(t, findCookie(cookies, middleware.CookieRefresh).HttpOnly)
+	assert.Equal(t, "/auth", findCookie(cookies, middleware.CookieRefresh).Path)
+	assert.False(t, findCookie(cookies, middleware.CookieCsrf).HttpOnly)
+	assert.Equal(t, auth.CsrfToken, findCookie(cookies, middleware.CookieCsrf).Value)
+
+	all := cookieHeader(cookies, middleware.CookieAccess, middleware.CookieRefresh, middleware.CookieCsrf)
+	resp = env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderCookie, all)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp = env.request(t, fiber.MethodPut, "/auth", nil, fiber.HeaderCookie, all, middleware.HeaderCsrf, auth.CsrfToken)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// The rotation replaces the cookies and the CSRF token.
+	rotated := &dto.AuthOutputDTO{}
+	decode(t, resp, rotated)
+	assert.NotEqual(t, auth.CsrfToken, rotated.CsrfToken)
+
+	all = cookieHeader(resp.Cookies(), middleware.CookieAccess, middleware.CookieRefresh, middleware.CookieCsrf)
+	resp = env.request(t, fiber.MethodDelete, "/auth", nil, fiber.HeaderCookie, all, middleware.HeaderCsrf, rotated.CsrfToken)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	assert.NotNil(t, env.sessions.sessions[0].RevokedAt)
+	assert.Empty(t, findCookie(resp.Cookies(), middleware.CookieAccess).Value)
+}
+
+// go test -run TestCookieModeCsrf
+func TestCookieModeCsrf(t *testing.T) {
+	env := newCookieTestEnv(t, &middleware.CookieConfig{SameSite: fiber.CookieSameSiteStrictMode})
+	user := env.addUser(t, "volunteer", &domain.Profile{Base: domain.Base{Id: 2}, Name: "VOLUNTEER"})
+
+	resp := env.request(t, fiber.MethodPost, "/auth", &dto.AuthInputDTO{Login: user.Email, Password: testPassword})
+	auth := &dto.AuthOutputDTO{}
+	decode(t, resp, auth)
+	cookies := resp.Cookies()
+	all := cookieHeader(cookies, middleware.CookieAccess, middleware.CookieRefresh, middleware.CookieCsrf)
+
+	// A page of another site makes the browser send the cookies, but it can not read the CSRF token.
+	for _, headers := range [][]string{
+		{fiber.HeaderCookie, all},
+		{fiber.HeaderCookie, all, middleware.HeaderCsrf, "forged"},
+		{fiber.HeaderCookie, cookieHeader(cookies, middleware.CookieAccess, middleware.CookieRefresh), middleware.HeaderCsrf, auth.CsrfToken},
+	} {
+		for _, method := range []string{fiber.MethodPut, fiber.MethodDelete} {
+			resp := env.request(t, method, "/auth", nil, headers...)
+			assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, method)
+
+			body := &httphelper.HTTPResponse{}
+			decode(t, resp, body)
+			assert.Equal(t, env.messages.ErrInvalidCsrfToken.Error(), body.Message)
+		}
+	}
+	assert.Nil(t, env.sessions.sessions[0].RevokedAt)
+
+	// The clients sending the token in the 'Authorization' header are not asked for it.
+	resp = env.request(t, fiber.MethodDelete, "/auth", nil, fiber.HeaderCookie, all, fiber.HeaderAuthorization, bearer(findCookie(cookies, middleware.CookieAccess).Value))
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+}
@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gofiber/fiber/v2"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/service"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/hasher"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/ipbinding"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+)
+
+// testPassword Password of the users of the tests.
+const testPassword = "Msaada#2026"
+
+type (
+	// fakeUserRepository Users kept in memory, the methods not used by the tests are left unimplemented.
+	fakeUserRepository struct {
+		domain.UserRepository
+		users map[uint]*domain.User
+	}
+
+	// fakeSessionRepository Sessions kept in memory, loaded with their users like the database does.
+	fakeSessionRepository struct {
+		domain.SessionRepository
+		users    *fakeUserRepository
+		sessions []*domain.Session
+	}
+
+	// fakeAttemptRepository Attempts kept in memory, locked by the policy like the database does.
+	fakeAttemptRepository struct {
+		attempts map[string]*domain.Attempt
+	}
+
+	// fakeAuditRepository Audit logs kept in memory, they can only be added.
+	fakeAuditRepository struct {
+		domain.AuditRepository
+		logs []domain.AuditLog
+	}
+
+	// fakeApiKeyRepository API keys kept in memory by their hash.
+	fakeApiKeyRepository struct {
+		domain.ApiKeyRepository
+		apiKeys map[string]*domain.ApiKey
+	}
+
+	// fakeProductRepository Products kept in memory, their mutations are audited like the database does.
+	fakeProductRepository struct {
+		domain.ProductRepository
+		audits   *fakeAuditRepository
+		products []*domain.Product
+	}
+
+	// testEnv API served by the handlers on top of the fake repositories.
+	testEnv struct {
+		app         *fiber.App
+		messages    *i18n.Translation
+		users       *fakeUserRepository
+		sessions    *fakeSessionRepository
+		attempts    *fakeAttemptRepository
+		audits      *fakeAuditRepository
+		apiKeys     *fakeApiKeyRepository
+		products    *fakeProductRepository
+		accessKeys  *keyring.Keyring
+		authService domain.AuthService
+	}
+)
+
+func (s *fakeUserRepository) GetUserByID(_ context.Context, userID uint) (*domain.User, error) {
+	if user, ok := s.users[userID]; ok {
+		return user, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeUserRepository) GetUserByMail(_ context.Context, mail string) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.Email == mail {
+			return user, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeSessionRepository) session(sessionID uint) *domain.Session {
+	for _, session := range s.sessions {
+		if session.Id == sessionID {
+			return session
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeSessionRepository) revoke(match func(*domain.Session) bool) {
+	now := time.Now()
+	for _, session := range s.sessions {
+		if session.RevokedAt == nil && match(session) {
+			session.RevokedAt = &now
+		}
+	}
+}
+
+func (s *fakeSessionRepository) GetSessionByToken(ctx context.Context, token string) (*domain.Session, error) {
+	for _, stored := range s.sessions {
+		if stored.Token != token {
+			continue
+		}
+
+		session := *stored
+		session.User, _ = s.users.GetUserByID(ctx, session.UserID)
+		if session.ImpersonatorID != nil {
+			session.Impersonator, _ = s.users.GetUserByID(ctx, *session.ImpersonatorID)
+		}
+		return &session, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeSessionRepository) CreateSession(_ context.Context, session *domain.Session) error {
+	session.Id = uint(len(s.sessions) + 1)
+	session.CreatedAt = time.Now()
+
+	stored := *session
+	stored.User, stored.Impersonator = nil, nil
+	s.sessions = append(s.sessions, &stored)
+	return nil
+}
+
+func (s *fakeSessionRepository) RotateSession(_ context.Context, session *domain.Session, refreshID string) error {
+	stored := s.session(session.Id)
+	if stored == nil || stored.RefreshID != refreshID || stored.RevokedAt != nil {
+		return domain.ErrTokenReused
+	}
+
+	stored.RefreshID, stored.IP, stored.LastSeen = session.RefreshID, session.IP, session.LastSeen
+	stored.ExpiresAt, stored.RefreshKeyID = session.ExpiresAt, session.RefreshKeyID
+	return nil
+}
+
+func (s *fakeSessionRepository) TouchSession(context.Context, *domain.Session) error {
+	return nil
+}
+
+func (s *fakeSessionRepository) RevokeSession(_ context.Context, session *domain.Session) error {
+	s.revoke(func(stored *domain.Session) bool {
+		return stored.Id == session.Id
+	})
+	return nil
+}
+
+func (s *fakeSessionRepository) RevokeUserSessions(_ context.Context, userID uint) error {
+	s.revoke(func(stored *domain.Session) bool {
+		return stored.UserID == userID || (stored.ImpersonatorID != nil && *stored.ImpersonatorID == userID)
+	})
+	return nil
+}
+
+func (s *fakeAttemptRepository) GetAttempt(_ context.Context, key string) (*domain.Attempt, error) {
+	if attempt, ok := s.attempts[key]; ok {
+		return attempt, nil
+	}
+
+	return &domain.Attempt{Key: key}, nil
+}
+
+func (s *fakeAttemptRepository) RegisterAttempt(_ context.Context, key string, policy *domain.AttemptPolicy) (*domain.Attempt, error) {
+	attempt, ok := s.attempts[key]
+	if !ok || time.Since(attempt.UpdatedAt) > policy.Window {
+		attempt = &domain.Attempt{Key: key}
+		s.attempts[key] = attempt
+	}
+
+	attempt.Count++
+	attempt.UpdatedAt = time.Now()
+	if lock := policy.LockFor(attempt.Count); lock > 0 {
+		lockedUntil := time.Now().Add(lock)
+		attempt.LockedUntil = &lockedUntil
+	}
+
+	return attempt, nil
+}
+
+func (s *fakeAttemptRepository) ResetAttempts(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s.attempts, key)
+	}
+
+	return nil
+}
+
+func (s *fakeAuditRepository) CreateAuditLog(_ context.Context, log *domain.AuditLog) error {
+	log.Id = uint(len(s.logs) + 1)
+	log.CreatedAt = time.Now()
+	s.logs = append(s.logs, *log)
+	return nil
+}
+
+func (s *fakeAuditRepository) CountAuditLogs(context.Context, *filter.Filter) (int64, error) {
+	return int64(len(s.logs)), nil
+}
+
+func (s *fakeAuditRepository) GetAuditLogs(context.Context, *filter.Filter) (*[]domain.AuditLog, error) {
+	logs := slices.Clone(s.logs)
+	return &logs, nil
+}
+
+func (s *fakeApiKeyRepository) GetApiKeyByHash(_ context.Context, hash string) (*domain.ApiKey, error) {
+	if apiKey, ok := s.apiKeys[hash]; ok {
+		return apiKey, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeApiKeyRepository) TouchApiKey(context.Context, *domain.ApiKey) error {
+	return nil
+}
+
+func (s *fakeProductRepository) CreateProduct(ctx context.Context, data *dto.ProductInputDTO) (*domain.Product, error) {
+	product := &domain.Product{}
+	if err := product.Bind(data); err != nil {
+		return nil, err
+	}
+
+	product.Id = uint(len(s.products) + 1)
+	s.products = append(s.products, product)
+	return product, s.audits.CreateAuditLog(ctx, domain.NewAuditLog(ctx, domain.AuditCreate, domain.ProductTableName, product.Id, nil, product.ToMap()))
+}
+
+// testMessages Loads the english messages of the responses.
+func testMessages(t *testing.T) *i18n.Translation {
+	bundle := goi18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+
+	_, err := bundle.LoadMessageFile(filepath.Join("..", "..", "..", "configs", "i18n", "active.en.toml"))
+	assert.Nil(t, err)
+
+	return i18n.NewTranslation(goi18n.NewLocalizer(bundle, "en"))
+}
+
+func testKeyring(t *testing.T) *keyring.Keyring {
+	key, err := keyring.GenerateKey(1024)
+	assert.Nil(t, err)
+
+	keys, err := keyring.New(context.Background(), func(context.Context) ([]*keyring.Key, error) {
+		return []*keyring.Key{key}, nil
+	})
+	assert.Nil(t, err)
+
+	return keys
+}
+
+// newTestEnv Wires the handlers as 'HandleRequests' does, the rate limits are left out.
+func newTestEnv(t *testing.T) *testEnv {
+	return newCookieTestEnv(t, nil)
+}
+
+// newCookieTestEnv Wires the handlers with the cookie mode of the settings, disabled when nil.
+func newCookieTestEnv(t *testing.T, cookies *middleware.CookieConfig) *testEnv {
+	users := &fakeUserRepository{users: map[uint]*domain.User{}}
+	audits := &fakeAuditRepository{}
+	env := &testEnv{
+		users:    users,
+		sessions: &fakeSessionRepository{users: users},
+		attempts: &fakeAttemptRepository{attempts: map[string]*domain.Attempt{}},
+		audits:   audits,
+		apiKeys:  &fakeApiKeyRepository{apiKeys: map[string]*domain.ApiKey{}},
+		products: &fakeProductRepository{audits: audits},
+	}
+
+	refreshKeys := testKeyring(t)
+	env.accessKeys = testKeyring(t)
+	env.authService = service.NewAuthService(env.users, nil, env.sessions, nil, env.attempts, nil, env.audits, env.accessKeys, refreshKeys, nil)
+
+	next := func(c *fiber.Ctx) error {
+		return c.Next()
+	}
+	middleware.MidAccess = middleware.Auth(env.accessKeys, env.sessions, ipbinding.Strict, false)
+	middleware.MidRefresh = middleware.Auth(refreshKeys, env.sessions, ipbinding.Strict, true)
+	middleware.MidTwoFactor = middleware.TwoFactor(env.accessKeys, env.users, ipbinding.Strict)
+
+	if middleware.AuthCookies = cookies; cookies != nil {
+		middleware.MidAccess = cookies.Auth(middleware.CookieAccess, middleware.MidAccess)
+		middleware.MidRefresh = cookies.Auth(middleware.CookieRefresh, middleware.MidRefresh)
+	}
+
+	middleware.MidResource = middleware.Resource(middleware.ApiKey(env.apiKeys), middleware.MidAccess)
+	middleware.MidRateLimit, middleware.MidAuthRateLimit = next, next
+
+	env.messages = testMessages(t)
+	env.app = fiber.New()
+	env.app.Use(func(c *fiber.Ctx) error {
+		c.Locals(httphelper.LocalLang, env.messages)
+		return c.Next()
+	})
+
+	// The routes loading their item from the database are not served.
+	reqMid := middleware.NewRequesttMiddleware(nil)
+	NewAuthHandler(env.app.Group("/auth"), env.authService, service.NewSessionService(env.sessions))
+	NewProductHandler(env.app.Group("/product"), service.NewProductService(env.products), reqMid)
+	NewAuditHandler(env.app.Group("/audit"), service.NewAuditService(env.audits))
+	return env
+}
+
+// addUser Stores an enabled user of the profile, with 'testPassword' as its password.
+func (s *testEnv) addUser(t *testing.T, name string, profile *domain.Profile) *domain.User {
+	hash, err := hasher.Hash(testPassword)
+	assert.Nil(t, err)
+
+	user := &domain.User{
+		Base:      domain.Base{Id: uint(len(s.users.users) + 1)},
+		Name:      name,
+		Email:     name + "@msaada.org",
+		Status:    true,
+		ProfileID: profile.Id,
+		Profile:   profile,
+		Password:  &hash,
+	}
+	s.users.users[user.Id] = user
+	return user
+}
+
+// request Sends the request with the JSON body, when not nil, and the headers given in name and value pairs.
+func (s *testEnv) request(t *testing.T, method, path string, body interface{}, headers ...string) *http.Response {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		assert.Nil(t, err)
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := s.app.Test(req, -1)
+	assert.Nil(t, err)
+	return resp
+}
+
+// decode Reads the JSON body of the response.
+func decode(t *testing.T, resp *http.Response, out interface{}) {
+	defer resp.Body.Close()
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(out))
+}
+
+// login Authenticates the user by mail and 'testPassword', returning the tokens.
+func (s *testEnv) login(t *testing.T, user *domain.User) *dto.AuthOutputDTO {
+	resp := s.request(t, fiber.MethodPost, "/auth", &dto.AuthInputDTO{Login: user.Email, Password: testPassword})
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	auth := &dto.AuthOutputDTO{}
+	decode(t, resp, auth)
+	return auth
+}
+
+func bearer(token string) string {
+	return "Bearer " + token
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+)
+
+type KeyHandler struct {
+	accessKeys *keyring.Keyring
+}
+
+// NewKeyHandler Creates a new handler publishing the keys that verify the access tokens.
+func NewKeyHandler(route fiber.Router, accessKeys *keyring.Keyring) {
+	handler := &KeyHandler{
+		accessKeys: accessKeys,
+	}
+
+	route.Get("/jwks.json", handler.getJWKS)
+}
+
+// getJWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys that verify the access tokens, selected by the 'kid' header of the token
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  keyring.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *KeyHandler) getJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=60")
+	return c.Status(fiber.StatusOK).JSON(h.accessKeys.JWKS())
+}
@@ -41,6 +41,10 @@ func (h *ProductHandler) handlerError(c *fiber.Ctx, err error) error {
 		return h.foreignKeyViolatedMethod(c, translation)
 	case errors.Is(err, pgerror.ErrUndefinedColumn):
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUndefinedColumn)
+	case errors.Is(err, filter.ErrInvalidSort):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidSort)
+	case errors.Is(err, filter.ErrInvalidCursor):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidCursor)
 	}
 
 	if errors.As(err, &validator.ErrValidator) {
@@ -57,11 +61,11 @@ func NewProductHandler(route fiber.Router, ps domain.ProductService, mid *middle
 		productService: ps,
 	}
 
-	route.Use(middleware.MidAccess)
+	route.Use(middleware.MidResource, middleware.MidRateLimit, middleware.CheckPermission(domain.ModuleProduct))
 
-	route.Get("", middleware.GetGenericFilter, handler.getProducts)
+	route.Get("", middleware.GetFieldset(nil), middleware.GetFilter(domain.ProductFilterFields), handler.getProducts)
 	route.Post("", middleware.GetProductDTO, handler.createProduct)
-	route.Get("/:"+httphelper.ParamID, mid.ProductByID, handler.getProductBydID)
+	route.Get("/:"+httphelper.ParamID, middleware.GetFieldset(nil), mid.ProductByID, handler.getProductBydID)
 	route.Put("/:"+httphelper.ParamID, mid.ProductByID, middleware.GetProductDTO, handler.updateProduct)
 	route.Delete("/:"+httphelper.ParamID, mid.ProductByID, handler.deleteProduct)
 }
@@ -74,10 +78,18 @@ func NewProductHandler(route fiber.Router, ps domain.ProductService, mid *middle
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        filter query filter.Filter false "Optional Filter"
+// @Param        id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        name query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        created_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        updated_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
 // @Success      200  {array}   dto.ListItemsOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /product [get]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProductHandler) getProducts(c *fiber.Ctx) error {
 	response, err := h.productService.GetProducts(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.Filter))
 	if err != nil {
@@ -95,12 +107,15 @@ func (h *ProductHandler) getProducts(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        id   path			int			true        "Product ID"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
 // @Success      200  {object}  dto.ProductOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /product/{id} [get]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProductHandler) getProductBydID(c *fiber.Ctx) error {
 	product := c.Locals(httphelper.LocalObject).(*domain.Product)
 	return c.Status(fiber.StatusOK).JSON(&dto.ProductOutputDTO{
@@ -120,9 +135,11 @@ func (h *ProductHandler) getProductBydID(c *fiber.Ctx) error {
 // @Success      201  {object}  dto.ProductOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      409  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /product [post]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProductHandler) createProduct(c *fiber.Ctx) error {
 	productDTO := c.Locals(httphelper.LocalDTO).(*dto.ProductInputDTO)
 	product, err := h.productService.CreateProduct(c.Context(), productDTO)
@@ -145,9 +162,11 @@ func (h *ProductHandler) createProduct(c *fiber.Ctx) error {
 // @Success      200  {object}  dto.ProductOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /product/{id} [put]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProductHandler) updateProduct(c *fiber.Ctx) error {
 	productDTO := c.Locals(httphelper.LocalDTO).(*dto.ProductInputDTO)
 	oldProduct := c.Locals(httphelper.LocalObject).(*domain.Product)
@@ -169,9 +188,11 @@ func (h *ProductHandler) updateProduct(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "Product ID"
 // @Success      204  {object}  nil
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /product/{id} [delete]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProductHandler) deleteProduct(c *fiber.Ctx) error {
 	product := c.Locals(httphelper.LocalObject).(*domain.Product)
 	if err := h.productService.DeleteProduct(c.Context(), product); err != nil {
@@ -41,6 +41,18 @@ func (h *ProfileHandler) handlerError(c *fiber.Ctx, err error) error {
 		return h.foreignKeyViolatedMethod(c, messages)
 	case errors.Is(err, pgerror.ErrUndefinedColumn):
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrUndefinedColumn)
+	case errors.Is(err, filter.ErrInvalidSort):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidSort)
+	case errors.Is(err, filter.ErrInvalidCursor):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidCursor)
+	}
+
+	if errors.Is(err, domain.ErrInvalidModule) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidModule)
+	}
+
+	if errors.Is(err, domain.ErrGrantForbidden) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrGrantForbidden)
 	}
 
 	if errors.As(err, &validator.ErrValidator) {
@@ -57,13 +69,13 @@ func NewProfileHandler(route fiber.Router, ps domain.ProfileService, mid *middle
 		profileService: ps,
 	}
 
-	route.Use(middleware.MidAccess)
+	route.Use(middleware.MidResource, middleware.MidRateLimit, middleware.CheckPermission(domain.ModuleProfile))
 
-	route.Get("", middleware.GetGenericFilter, handler.getProfiles)
-	route.Post("", middleware.GetProfileDTO, handler.createProfile)
-	route.Get("/:"+httphelper.ParamID, mid.ProfileByID, handler.getProfile)
-	route.Put("/:"+httphelper.ParamID, mid.ProfileByID, middleware.GetProfileDTO, handler.updateProfile)
-	route.Delete("/:"+httphelper.ParamID, mid.ProfileByID, handler.deleteProfile)
+	route.Get("", middleware.GetFieldset(domain.ProfileRelations), middleware.GetFilter(domain.ProfileFilterFields), handler.getProfiles)
+	route.Post("", middleware.DenyImpersonation, middleware.GetProfileDTO, handler.createProfile)
+	route.Get("/:"+httphelper.ParamID, middleware.GetFieldset(domain.ProfileRelations), mid.ProfileByID, handler.getProfile)
+	route.Put("/:"+httphelper.ParamID, middleware.DenyImpersonation, mid.ProfileByID, middleware.GetProfileDTO, handler.updateProfile)
+	route.Delete("/:"+httphelper.ParamID, middleware.DenyImpersonation, mid.ProfileByID, handler.deleteProfile)
 }
 
 // getProfiles godoc
@@ -74,10 +86,20 @@ func NewProfileHandler(route fiber.Router, ps domain.ProfileService, mid *middle
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        filter query filter.Filter false "Optional Filter"
+// @Param        id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        name query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        two_factor query string false "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')"
+// @Param        created_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        updated_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Param        include query string false "Relations embedded, comma separated: 'permissions' (default)"
 // @Success      200  {array}   dto.ListItemsOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /profile [get]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProfileHandler) getProfiles(c *fiber.Ctx) error {
 	response, err := h.profileService.GetProfiles(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.Filter))
 	if err != nil {
@@ -98,12 +120,14 @@ func (h *ProfileHandler) getProfiles(c *fiber.Ctx) error {
 // @Success      201  {object}  dto.ProfileOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      409  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /profile [post]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProfileHandler) createProfile(c *fiber.Ctx) error {
 	profileDTO := c.Locals(httphelper.LocalDTO).(*dto.ProfileInputDTO)
-	profile, err := h.profileService.CreateProfile(c.Context(), profileDTO)
+	profile, err := h.profileService.CreateProfile(c.Context(), middleware.Grantor(c), profileDTO)
 	if err != nil {
 		return h.handlerError(c, err)
 	}
@@ -119,14 +143,20 @@ func (h *ProfileHandler) createProfile(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "Profile ID"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Param        include query string false "Relations embedded, comma separated: 'permissions' (default)"
 // @Success      200  {object}  dto.ProfileOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /profile/{id} [get]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProfileHandler) getProfile(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusOK).JSON(c.Locals(httphelper.LocalObject).(*domain.Profile))
+	profile := c.Locals(httphelper.LocalObject).(*domain.Profile)
+
+	return c.Status(fiber.StatusOK).JSON(h.profileService.GenerateProfileOutputDTO(profile))
 }
 
 // updateProfile godoc
@@ -141,13 +171,15 @@ func (h *ProfileHandler) getProfile(c *fiber.Ctx) error {
 // @Success      200  {object}  dto.ProfileOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /profile/{id} [put]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProfileHandler) updateProfile(c *fiber.Ctx) error {
 	profileDTO := c.Locals(httphelper.LocalDTO).(*dto.ProfileInputDTO)
 	oldProfile := c.Locals(httphelper.LocalObject).(*domain.Profile)
-	newProfile, err := h.profileService.UpdateProfile(c.Context(), oldProfile, profileDTO)
+	newProfile, err := h.profileService.UpdateProfile(c.Context(), middleware.Grantor(c), oldProfile, profileDTO)
 	if err != nil {
 		return h.handlerError(c, err)
 	}
@@ -165,9 +197,11 @@ func (h *ProfileHandler) updateProfile(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "Profile ID"
 // @Success      204  {object}  nil
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /profile/{id} [delete]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *ProfileHandler) deleteProfile(c *fiber.Ctx) error {
 	if err := h.profileService.DeleteProfile(c.Context(), c.Locals(httphelper.LocalObject).(*domain.Profile)); err != nil {
 		return h.handlerError(c, err)
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/validator"
+)
+
+type RegisterHandler struct {
+	userService domain.UserService
+}
+
+func (h *RegisterHandler) handlerError(c *fiber.Ctx, err error) error {
+	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	var locked *domain.LockedError
+	if errors.As(err, &locked) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(locked.Until).Seconds())+1))
+		return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrRegisterLocked)
+	}
+
+	if errors.Is(err, domain.ErrInvalidPasswordToken) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidPasswordToken)
+	}
+
+	if message := passwordPolicyError(messages, err); message != nil {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, message)
+	}
+
+	if errors.As(err, &validator.ErrValidator) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, err)
+	}
+
+	log.Println(err.Error())
+	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, messages.ErrGeneric)
+}
+
+// NewRegisterHandler Creates the public sign-up handler.
+func NewRegisterHandler(route fiber.Router, us domain.UserService) {
+	handler := &RegisterHandler{
+		userService: us,
+	}
+
+	route.Use(middleware.MidAuthRateLimit)
+
+	route.Post("", middleware.GetRegisterDTO, handler.register)
+	route.Post("/verify", middleware.GetVerifyDTO, handler.verify)
+}
+
+// register godoc
+// @Summary      Volunteer sign-up
+// @Description  Creates a disabled user in the volunteer profile and mails it the verification link. The answer is the same when the mail is already registered, the sign-ups are limited per IP and per mail
+// @Tags         Register
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        user body dto.RegisterInputDTO true "Sign-up model"
+// @Success      202  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      429  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /register [post]
+func (h *RegisterHandler) register(c *fiber.Ctx) error {
+	data := c.Locals(httphelper.LocalDTO).(*dto.RegisterInputDTO)
+	if !data.IsValid() {
+		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrPassUnmatch)
+	}
+
+	if err := h.userService.RegisterUser(c.Context(), data, c.IP()); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(nil)
+}
+
+// verify godoc
+// @Summary      Verify sign-up
+// @Description  Verifies the mail of the sign-up with the mailed token, the user is enabled unless a coordinator approval is required
+// @Tags         Register
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        verify body dto.VerifyInputDTO true "Verification model"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /register/verify [post]
+func (h *RegisterHandler) verify(c *fiber.Ctx) error {
+	if err := h.userService.VerifyUser(c.Context(), c.Locals(httphelper.LocalDTO).(*dto.VerifyInputDTO)); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+type SearchHandler struct {
+	searchService domain.SearchService
+}
+
+func (h *SearchHandler) handlerError(c *fiber.Ctx, err error) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	if errors.Is(err, filter.ErrInvalidSort) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidSort)
+	}
+
+	log.Println(err.Error())
+	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
+}
+
+// NewSearchHandler Creates the global search handler, the permissions are checked per module.
+func NewSearchHandler(route fiber.Router, ss domain.SearchService) {
+	handler := &SearchHandler{
+		searchService: ss,
+	}
+
+	route.Use(middleware.MidResource, middleware.MidRateLimit)
+
+	route.Get("", middleware.GetSearchFilter, handler.search)
+}
+
+// search godoc
+// @Summary      Global search
+// @Description  Searches the users, profiles and products readable by the caller in full text, ranked by relevance with the matches highlighted in 'headline'
+// @Tags         Search
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        filter query filter.Filter false "Search, 'limit' items of each module, five by default"
+// @Success      200  {object}  dto.SearchOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /search [get]
+// @Security	 Bearer
+// @Security	 ApiKey
+func (h *SearchHandler) search(c *fiber.Ctx) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+	search := c.Locals(httphelper.LocalFilter).(*filter.Filter)
+	if search.Search == "" {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrSearchRequired)
+	}
+
+	modules := []string{}
+	for _, module := range domain.SearchModules {
+		if middleware.Allowed(c, module, domain.ActionRead) {
+			modules = append(modules, module)
+		}
+	}
+	if len(modules) == 0 {
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, translation.ErrWithoutPermission)
+	}
+
+	response, err := h.searchService.Search(c.Context(), search, modules)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
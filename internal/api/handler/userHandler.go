@@ -14,12 +14,15 @@ import (
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
 	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/password"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/pgerror"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/validator"
 )
 
 type UserHandler struct {
-	userService domain.UserService
+	userService    domain.UserService
+	sessionService domain.SessionService
+	authService    domain.AuthService
 }
 
 func (h *UserHandler) foreignKeyViolatedFrom(c *fiber.Ctx, messages *i18n.Translation) error {
@@ -33,6 +36,29 @@ func (h *UserHandler) foreignKeyViolatedFrom(c *fiber.Ctx, messages *i18n.Transl
 	}
 }
 
+// passwordPolicyError Returns the message of the password rule broken, nil for any other error.
+func passwordPolicyError(messages *i18n.Translation, err error) error {
+	rules := map[error]error{
+		password.ErrTooShort:     messages.ErrPasswordTooShort,
+		password.ErrTooLong:      messages.ErrPasswordTooLong,
+		password.ErrNoUpper:      messages.ErrPasswordNoUpper,
+		password.ErrNoLower:      messages.ErrPasswordNoLower,
+		password.ErrNoDigit:      messages.ErrPasswordNoDigit,
+		password.ErrNoSymbol:     messages.ErrPasswordNoSymbol,
+		password.ErrPersonal:     messages.ErrPasswordPersonal,
+		password.ErrCommon:       messages.ErrPasswordCommon,
+		domain.ErrPasswordReused: messages.ErrPasswordReused,
+	}
+
+	for rule, message := range rules {
+		if errors.Is(err, rule) {
+			return message
+		}
+	}
+
+	return nil
+}
+
 func (h *UserHandler) handlerError(c *fiber.Ctx, err error) error {
 	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 
@@ -43,6 +69,30 @@ func (h *UserHandler) handlerError(c *fiber.Ctx, err error) error {
 		return h.foreignKeyViolatedFrom(c, messages)
 	case errors.Is(err, pgerror.ErrUndefinedColumn):
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrUndefinedColumn)
+	case errors.Is(err, filter.ErrInvalidSort):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidSort)
+	case errors.Is(err, filter.ErrInvalidCursor):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidCursor)
+	}
+
+	if errors.Is(err, domain.ErrInvalidPasswordToken) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidPasswordToken)
+	}
+
+	if errors.Is(err, domain.ErrImpersonationForbidden) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrImpersonationForbidden)
+	}
+
+	if errors.Is(err, domain.ErrGrantForbidden) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrGrantForbidden)
+	}
+
+	if errors.Is(err, domain.ErrUserNotVerified) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusConflict, messages.ErrUserNotVerified)
+	}
+
+	if message := passwordPolicyError(messages, err); message != nil {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, message)
 	}
 
 	if errors.As(err, &validator.ErrValidator) {
@@ -71,21 +121,28 @@ func (h *UserHandler) getUserByEmail(c *fiber.Ctx) error {
 }
 
 // NewUserHandler Creates a new user handler.
-func NewUserHandler(route fiber.Router, us domain.UserService, mid *middleware.RequesttMiddleware) {
+func NewUserHandler(route fiber.Router, us domain.UserService, ss domain.SessionService, as domain.AuthService, mid *middleware.RequesttMiddleware) {
 	handler := &UserHandler{
-		userService: us,
+		userService:    us,
+		sessionService: ss,
+		authService:    as,
 	}
 
-	route.Patch("/:"+httphelper.ParamMail+"/passw", handler.getUserByEmail, middleware.GetPasswordInputDTO, handler.setUserPassword)
+	route.Patch("/:"+httphelper.ParamMail+"/passw", middleware.MidAuthRateLimit, handler.getUserByEmail, middleware.GetPasswordInputDTO, handler.setUserPassword)
 
-	route.Use(middleware.MidAccess)
+	route.Use(middleware.MidResource, middleware.MidRateLimit, middleware.CheckPermission(domain.ModuleUser))
 
-	route.Get("", middleware.GetUserFilter, handler.getUsers)
-	route.Post("", middleware.GetUserDTO, handler.createUser)
-	route.Get("/:"+httphelper.ParamID, mid.UserByID, handler.getUser)
-	route.Put("/:"+httphelper.ParamID, mid.UserByID, middleware.GetUserDTO, handler.updateUser)
+	route.Get("", middleware.GetFieldset(domain.UserRelations), middleware.GetUserFilter, handler.getUsers)
+	route.Post("", middleware.DenyImpersonation, middleware.GetUserDTO, handler.createUser)
+	route.Get("/:"+httphelper.ParamID, middleware.GetFieldset(domain.UserRelations), mid.UserByID, handler.getUser)
+	route.Put("/:"+httphelper.ParamID, middleware.DenyImpersonation, mid.UserByID, middleware.GetUserDTO, handler.updateUser)
 	route.Delete("/:"+httphelper.ParamID, mid.UserByID, handler.deleteUser)
-	route.Patch("/:"+httphelper.ParamID+"/reset", mid.UserByID, handler.resetUserPassword)
+	route.Patch("/:"+httphelper.ParamID+"/reset", middleware.DenyImpersonation, mid.UserByID, handler.resetUserPassword)
+	route.Patch("/:"+httphelper.ParamID+"/unlock", mid.UserByID, handler.unlockUser)
+	route.Patch("/:"+httphelper.ParamID+"/approve", mid.UserByID, handler.approveUser)
+	route.Get("/:"+httphelper.ParamID+"/sessions", mid.UserByID, handler.getUserSessions)
+	route.Delete("/:"+httphelper.ParamID+"/sessions", mid.UserByID, handler.revokeUserSessions)
+	route.Post("/:"+httphelper.ParamID+"/impersonate", middleware.DenyImpersonation, mid.UserByID, handler.impersonateUser)
 }
 
 // getUsers godoc
@@ -96,10 +153,22 @@ func NewUserHandler(route fiber.Router, us domain.UserService, mid *middleware.R
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        filter query filter.UserFilter false "Optional Filter"
+// @Param        id query string false "Int filter 'operator:value', operators eq, ne, in, gt, gte, lt, lte, between, isnull (e.g. 'gte:10')"
+// @Param        name query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        mail query string false "String filter 'operator:value', operators eq, ne, in, isnull (e.g. 'in:a,b')"
+// @Param        status query string false "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')"
+// @Param        new query string false "Bool filter 'operator:value', operators eq, ne, isnull (e.g. 'eq:true')"
+// @Param        created_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        updated_at query string false "Date or RFC 3339 filter 'operator:value', operators eq, ne, gt, gte, lt, lte, between, isnull (e.g. 'gte:2026-01-01')"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Param        include query string false "Relations embedded, comma separated: 'profile' (default), 'profile.permissions' (default)"
 // @Success      200  {array}   dto.ListItemsOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user [get]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *UserHandler) getUsers(c *fiber.Ctx) error {
 	response, err := h.userService.GetUsers(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.UserFilter))
 	if err != nil {
@@ -120,12 +189,14 @@ func (h *UserHandler) getUsers(c *fiber.Ctx) error {
 // @Success      201  {object}  dto.UserOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      409  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user [post]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *UserHandler) createUser(c *fiber.Ctx) error {
 	userDTO := c.Locals(httphelper.LocalDTO).(*dto.UserInputDTO)
-	user, err := h.userService.CreateUser(c.Context(), userDTO)
+	user, err := h.userService.CreateUser(c.Context(), middleware.Grantor(c), userDTO)
 	if err != nil {
 		return h.handlerError(c, err)
 	}
@@ -141,25 +212,22 @@ func (h *UserHandler) createUser(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "User ID"
+// @Param        fields query string false "Fields of the response, comma separated, nested after their parent (e.g. 'id,name,profile.name')"
+// @Param        include query string false "Relations embedded, comma separated: 'profile' (default), 'profile.permissions' (default)"
 // @Success      200  {object}  dto.UserOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user/{id} [get]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *UserHandler) getUser(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalObject).(*domain.User)
 
-	return c.Status(fiber.StatusOK).JSON(&dto.UserOutputDTO{
-		Id:     user.Id,
-		Name:   user.Name,
-		Email:  user.Name,
-		Status: user.Status,
-		Profile: dto.ProfileOutputDTO{
-			Id:   user.ProfileID,
-			Name: user.Profile.Name,
-		},
-	})
+	fieldset, _ := c.Locals(httphelper.LocalFieldset).(*filter.Fieldset)
+
+	return c.Status(fiber.StatusOK).JSON(h.userService.GenerateUserOutputDTO(user, fieldset))
 }
 
 // updateUser godoc
@@ -174,13 +242,15 @@ func (h *UserHandler) getUser(c *fiber.Ctx) error {
 // @Success      200  {object}  dto.UserOutputDTO
 // @Failure      400  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user/{id} [put]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *UserHandler) updateUser(c *fiber.Ctx) error {
 	userDTO := c.Locals(httphelper.LocalDTO).(*dto.UserInputDTO)
 	oldUser := c.Locals(httphelper.LocalObject).(*domain.User)
-	newUser, err := h.userService.UpdateUser(c.Context(), oldUser, userDTO)
+	newUser, err := h.userService.UpdateUser(c.Context(), middleware.Grantor(c), oldUser, userDTO)
 	if err != nil {
 		return h.handlerError(c, err)
 	}
@@ -198,9 +268,11 @@ func (h *UserHandler) updateUser(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "User ID"
 // @Success      204  {object}  nil
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user/{id} [delete]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
 	if err := h.userService.DeleteUser(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User)); err != nil {
 		return h.handlerError(c, err)
@@ -211,7 +283,7 @@ func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
 
 // resetUser godoc
 // @Summary      Reset user password
-// @Description  Reset user password by ID
+// @Description  Reset user password by ID and mail a reset link, users without password receive the invitation again
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -219,25 +291,151 @@ func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "User ID"
 // @Success      200  {object}  nil
 // @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user/{id}/reset [patch]
 // @Security	 Bearer
+// @Security	 ApiKey
 func (h *UserHandler) resetUserPassword(c *fiber.Ctx) error {
+	if err := h.userService.ResetUserPassword(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User)); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(nil)
+}
+
+// unlockUser godoc
+// @Summary      Unlock user
+// @Description  Clears the failed login attempts of the user by ID, lifting the lockout
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "User ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /user/{id}/unlock [patch]
+// @Security	 Bearer
+// @Security	 ApiKey
+func (h *UserHandler) unlockUser(c *fiber.Ctx) error {
+	if err := h.userService.UnlockUser(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User)); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// approveUser godoc
+// @Summary      Approve user
+// @Description  Enables the self registered user by ID once its mail is verified
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "User ID"
+// @Success      200  {object}  dto.UserOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      409  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /user/{id}/approve [patch]
+// @Security	 Bearer
+// @Security	 ApiKey
+func (h *UserHandler) approveUser(c *fiber.Ctx) error {
+	user, err := h.userService.ApproveUser(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User))
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(user)
+}
+
+// getUserSessions godoc
+// @Summary      Get user sessions
+// @Description  Active sessions of the user by ID
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "User ID"
+// @Success      200  {array}   dto.SessionOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /user/{id}/sessions [get]
+// @Security	 Bearer
+// @Security	 ApiKey
+func (h *UserHandler) getUserSessions(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalObject).(*domain.User)
+	sessions, err := h.sessionService.GetUserSessions(c.Context(), user, nil)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
 
-	if !user.New {
-		if err := h.userService.ResetUserPassword(c.Context(), user); err != nil {
-			return h.handlerError(c, err)
-		}
-		return c.Status(fiber.StatusOK).JSON(nil)
+	return c.Status(fiber.StatusOK).JSON(sessions)
+}
+
+// revokeUserSessions godoc
+// @Summary      Revoke user sessions
+// @Description  Revokes every session of the user by ID
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "User ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /user/{id}/sessions [delete]
+// @Security	 Bearer
+// @Security	 ApiKey
+func (h *UserHandler) revokeUserSessions(c *fiber.Ctx) error {
+	if err := h.sessionService.RevokeUserSessions(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User)); err != nil {
+		return h.handlerError(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(nil)
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// impersonateUser godoc
+// @Summary      Impersonate user
+// @Description  Issues a short-lived access token of the user by ID for the authenticated admin, whose profile must grant every permission of the user. The token carries the admin in its 'act' claim, it can not change passwords, permissions or credentials and the audit log records the admin as the actor
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "User ID"
+// @Success      200  {object}  dto.AuthOutputDTO
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      403  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.HTTPResponse
+// @Router       /user/{id}/impersonate [post]
+// @Security	 Bearer
+func (h *UserHandler) impersonateUser(c *fiber.Ctx) error {
+	admin, ok := c.Locals(httphelper.LocalUser).(*domain.User)
+	if !ok {
+		return h.handlerError(c, domain.ErrImpersonationForbidden)
+	}
+
+	response, err := h.authService.Impersonate(c.Context(), admin, c.Locals(httphelper.LocalObject).(*domain.User), c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 // passwordUser godoc
 // @Summary      Set user password
-// @Description  Set user password by ID
+// @Description  Set user password with the one time token mailed by the invitation or the reset, the password must follow the password policy
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -245,6 +443,8 @@ func (h *UserHandler) resetUserPassword(c *fiber.Ctx) error {
 // @Param        email     path    string     true        "User email"
 // @Param        password body dto.PasswordInputDTO true "Password model"
 // @Success      200  {object}  nil
+// @Failure      400  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.HTTPResponse
 // @Failure      404  {object}  httphelper.HTTPResponse
 // @Failure      500  {object}  httphelper.HTTPResponse
 // @Router       /user/{email}/passw [patch]
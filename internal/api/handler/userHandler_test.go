@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/api/middleware"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+// root Profile granted every action on every module.
+func root() *domain.Profile {
+	profile := &domain.Profile{Base: domain.Base{Id: 1}, Name: "ROOT"}
+	for _, module := range domain.Modules {
+		profile.Permissions = append(profile.Permissions, domain.Permission{Module: module, Read: true, Create: true, Update: true, Delete: true})
+	}
+
+	return profile
+}
+
+// volunteer Profile reading and creating the products.
+func volunteer() *domain.Profile {
+	return &domain.Profile{
+		Base:        domain.Base{Id: 2},
+		Name:        "VOLUNTEER",
+		Permissions: []domain.Permission{{Module: domain.ModuleProduct, Read: true, Create: true}},
+	}
+}
+
+// userByID Loads the user of the route from the fake repository, in place of 'RequesttMiddleware.UserByID'.
+func (s *testEnv) userByID(c *fiber.Ctx) error {
+	id, _ := c.ParamsInt(httphelper.ParamID)
+	user, err := s.users.GetUserByID(c.Context(), uint(id))
+	if err != nil {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	c.Locals(httphelper.LocalObject, user)
+	return c.Next()
+}
+
+// serveImpersonation Serves the impersonation route with the middlewares of 'NewUserHandler'.
+func (s *testEnv) serveImpersonation() {
+	handler := &UserHandler{authService: s.authService}
+	s.app.Post("/user/:"+httphelper.ParamID+"/impersonate", middleware.MidResource, middleware.CheckPermission(domain.ModuleUser),
+		middleware.DenyImpersonation, s.userByID, handler.impersonateUser)
+}
+
+// impersonate Opens the impersonation of the user by the admin, returning its access token.
+func (s *testEnv) impersonate(t *testing.T, admin, user *domain.User) string {
+	token := bearer(s.login(t, admin).AccessToken)
+	resp := s.request(t, fiber.MethodPost, "/user/"+strconv.Itoa(int(user.Id))+"/impersonate", nil, fiber.HeaderAuthorization, token)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	auth := &dto.AuthOutputDTO{}
+	decode(t, resp, auth)
+	assert.Equal(t, admin.Id, *auth.User.ImpersonatorID)
+	assert.Empty(t, auth.RefreshToken)
+	return auth.AccessToken
+}
+
+// go test -run TestImpersonationClaims
+func TestImpersonationClaims(t *testing.T) {
+	env := newTestEnv(t)
+	env.serveImpersonation()
+	admin := env.addUser(t, "administrator", root())
+	user := env.addUser(t, "volunteer", volunteer())
+
+	token := env.impersonate(t, admin, user)
+
+	// The token is the user's, carrying the admin as its actor, and ends with the short session.
+	claims, err := domain.ParseClaims(token, env.accessKeys.Keyfunc, domain.TokenAccess)
+	assert.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(int(user.Id)), claims.Subject)
+	assert.Equal(t, strconv.Itoa(int(admin.Id)), claims.Actor.Subject)
+	assert.WithinDuration(t, time.Now().Add(domain.ImpersonationLife()), claims.ExpiresAt.Time, time.Minute)
+
+	resp := env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderAuthorization, bearer(token))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	me := &dto.UserOutputDTO{}
+	decode(t, resp, me)
+	assert.Equal(t, user.Id, me.Id)
+	assert.Equal(t, admin.Id, *me.ImpersonatorID)
+
+	// The passwords and credentials are not changed by an impersonation.
+	resp = env.request(t, fiber.MethodPatch, "/auth/password", nil, fiber.HeaderAuthorization, bearer(token))
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	body := &httphelper.HTTPResponse{}
+	decode(t, resp, body)
+	assert.Equal(t, env.messages.ErrImpersonationDenied.Error(), body.Message)
+}
+
+// go test -run TestImpersonationAudit
+func TestImpersonationAudit(t *testing.T) {
+	env := newTestEnv(t)
+	env.serveImpersonation()
+	admin := env.addUser(t, "administrator", root())
+	user := env.addUser(t, "volunteer", volunteer())
+
+	token := env.impersonate(t, admin, user)
+
+	opened := env.audits.logs[len(env.audits.logs)-1]
+	assert.Equal(t, domain.AuditImpersonate, opened.Action)
+	assert.Equal(t, user.Id, *opened.EntityID)
+	assert.Equal(t, admin.Id, *opened.ActorID)
+
+	// What is done in the session of the user is recorded as done by the admin.
+	name := "Water filters"
+	resp := env.request(t, fiber.MethodPost, "/product", &dto.ProductInputDTO{Name: &name}, fiber.HeaderAuthorization, bearer(token))
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	created := env.audits.logs[len(env.audits.logs)-1]
+	assert.Equal(t, domain.AuditCreate, created.Action)
+	assert.Equal(t, admin.Id, *created.ActorID)
+	assert.Equal(t, user.Id, *created.ImpersonatedID)
+}
+
+// go test -run TestImpersonationForbidden
+func TestImpersonationForbidden(t *testing.T) {
+	env := newTestEnv(t)
+	env.serveImpersonation()
+
+	// The admin can manage the users but not the products the volunteer creates.
+	manager := &domain.Profile{Base: domain.Base{Id: 4}, Name: "MANAGER", Permissions: []domain.Permission{{Module: domain.ModuleUser, Read: true, Create: true}}}
+	admin := env.addUser(t, "manager", manager)
+	user := env.addUser(t, "volunteer", volunteer())
+
+	token := bearer(env.login(t, admin).AccessToken)
+	resp := env.request(t, fiber.MethodPost, "/user/"+strconv.Itoa(int(user.Id))+"/impersonate", nil, fiber.HeaderAuthorization, token)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	body := &httphelper.HTTPResponse{}
+	decode(t, resp, body)
+	assert.Equal(t, env.messages.ErrImpersonationForbidden.Error(), body.Message)
+	assert.Len(t, env.sessions.sessions, 1)
+}
+
+// go test -run TestImpersonationEnded
+func TestImpersonationEnded(t *testing.T) {
+	env := newTestEnv(t)
+	env.serveImpersonation()
+	admin := env.addUser(t, "administrator", root())
+	user := env.addUser(t, "volunteer", volunteer())
+
+	// The impersonation ends with the admin being disabled.
+	token := env.impersonate(t, admin, user)
+	admin.Status = false
+	resp := env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderAuthorization, bearer(token))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	admin.Status = true
+
+	// And with the sessions of the admin being revoked.
+	token = env.impersonate(t, admin, user)
+	adminToken := bearer(env.login(t, admin).AccessToken)
+	resp = env.request(t, fiber.MethodDelete, "/auth/all", nil, fiber.HeaderAuthorization, adminToken)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp = env.request(t, fiber.MethodGet, "/auth", nil, fiber.HeaderAuthorization, bearer(token))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
@@ -1,12 +1,14 @@
 package middleware
 
 import (
-	"encoding/base64"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
-	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/ipbinding"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/keyauth"
@@ -15,16 +17,60 @@ import (
 )
 
 var (
-	MidAccess  fiber.Handler
-	MidRefresh fiber.Handler
+	MidAccess    fiber.Handler
+	MidRefresh   fiber.Handler
+	MidTwoFactor fiber.Handler
+	MidResource  fiber.Handler
 )
 
-func Auth(base64key string, repo domain.UserRepository) fiber.Handler {
-	decodedKey, err := base64.StdEncoding.DecodeString(base64key)
-	helpers.PanicIfErr(err)
+// touchInterval Minimum time between two updates of the session last seen time.
+const touchInterval = time.Minute
 
-	parsedKey, err := jwt.ParseRSAPublicKeyFromPEM(decodedKey)
-	helpers.PanicIfErr(err)
+func authError(c *fiber.Ctx, err error) error {
+	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidIpAssociation):
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidIpAssociation)
+	case errors.Is(err, domain.ErrTokenReused):
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrTokenReused)
+	case errors.Is(err, domain.ErrInvalidSession):
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidSession)
+	case errors.Is(err, domain.ErrInvalidTokenType):
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidTokenType)
+	case errors.Is(err, domain.ErrInvalidApiKey):
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, messages.ErrInvalidApiKey)
+	case errors.Is(err, domain.ErrImpersonationDenied):
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrImpersonationDenied)
+	case errors.Is(err, ErrInvalidCsrfToken):
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrInvalidCsrfToken)
+	}
+
+	return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, err)
+}
+
+// parseClaims Validates the JWT, its type and its association with the request IP.
+func parseClaims(c *fiber.Ctx, key string, keys *keyring.Keyring, binding ipbinding.Mode, tokenType string) (*domain.Claims, error) {
+	claims, err := domain.ParseClaims(key, keys.Keyfunc, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !binding.Match(claims.IP, c.IP()) {
+		return nil, domain.ErrInvalidIpAssociation
+	}
+
+	return claims, nil
+}
+
+// Auth Validates the JWT and its session, 'refresh' marks the refresh tokens whose ID must match
+// the last rotation of the session, any other ID means it was reused and the session is revoked.
+// The admin of an impersonation is stored in 'LocalImpersonator'.
+func Auth(keys *keyring.Keyring, repo domain.SessionRepository, binding ipbinding.Mode, refresh bool) fiber.Handler {
+	tokenType := domain.TokenAccess
+	if refresh {
+		tokenType = domain.TokenRefresh
+	}
 
 	return keyauth.New(keyauth.Config{
 		KeyLookup:  "header:" + fiber.HeaderAuthorization,
@@ -38,43 +84,141 @@ func Auth(base64key string, repo domain.UserRepository) fiber.Handler {
 		SuccessHandler: func(c *fiber.Ctx) error {
 			return c.Next()
 		},
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, err)
-		},
+		ErrorHandler: authError,
 		Validator: func(c *fiber.Ctx, key string) (bool, error) {
-			parsedToken, err := jwt.Parse(key, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-					return nil, err
-				}
+			claims, err := parseClaims(c, key, keys, binding, tokenType)
+			if err != nil {
+				return false, err
+			}
 
-				return parsedKey, nil
-			})
+			userID, err := claims.UserID()
 			if err != nil {
 				return false, err
 			}
 
-			claims, ok := parsedToken.Claims.(jwt.MapClaims)
-			if !ok || !parsedToken.Valid {
-				return false, errors.New("invalid jwt token")
+			actorID, err := claims.ActorID()
+			if err != nil {
+				return false, err
+			}
+
+			session, err := repo.GetSessionByToken(c.Context(), claims.Session)
+			if err != nil || !session.IsActive() || session.UserID != userID {
+				return false, domain.ErrInvalidSession
+			}
+
+			// Only the impersonation sessions accept, and require, the token of their admin, who must
+			// still be enabled.
+			if (session.ImpersonatorID == nil && actorID != 0) || (session.ImpersonatorID != nil && *session.ImpersonatorID != actorID) {
+				return false, domain.ErrInvalidSession
+			}
+			if session.ImpersonatorID != nil && (session.Impersonator == nil || !session.Impersonator.Status) {
+				return false, domain.ErrInvalidSession
+			}
+
+			if refresh {
+				if claims.ID != session.RefreshID {
+					_ = repo.RevokeSession(c.Context(), session)
+					return false, domain.ErrTokenReused
+				}
+			} else if time.Since(session.LastSeen) > touchInterval {
+				session.IP = c.IP()
+				session.LastSeen = time.Now()
+				_ = repo.TouchSession(c.Context(), session)
+			}
+
+			user := session.User
+			if !user.Status {
+				return false, errors.New("invalid user")
+			}
+			user.Expire = session.Expire
+
+			c.Locals(httphelper.LocalUser, user)
+			c.Locals(httphelper.LocalSession, session)
+			if actorID != 0 {
+				c.Locals(httphelper.LocalImpersonator, actorID)
 			}
+			return true, nil
+		},
+	})
+}
+
+// DenyImpersonation Refuses the request made by an admin impersonating the user, for the routes
+// changing passwords, permissions or credentials.
+func DenyImpersonation(c *fiber.Ctx) error {
+	if _, ok := c.Locals(httphelper.LocalImpersonator).(uint); ok {
+		return authError(c, domain.ErrImpersonationDenied)
+	}
+
+	return c.Next()
+}
 
-			user, err := repo.GetUserByToken(c.Context(), claims["token"].(string))
+// TwoFactor Validates the token issued by a login waiting for the second factor, it is not bound to a session.
+func TwoFactor(keys *keyring.Keyring, repo domain.UserRepository, binding ipbinding.Mode) fiber.Handler {
+	return keyauth.New(keyauth.Config{
+		KeyLookup:    "header:" + fiber.HeaderAuthorization,
+		AuthScheme:   "Bearer",
+		ContextKey:   "token",
+		ErrorHandler: authError,
+		Validator: func(c *fiber.Ctx, key string) (bool, error) {
+			claims, err := parseClaims(c, key, keys, binding, domain.TokenTwoFactor)
 			if err != nil {
 				return false, err
 			}
-			if val, ok := claims["ip"]; !ok || val.(string) != c.IP() {
-				return false, domain.ErrInvalidIpAssociation
+
+			userID, err := claims.UserID()
+			if err != nil {
+				return false, err
 			}
-			if val, ok := claims["expire"]; ok {
-				user.Expire = val.(bool)
+
+			user, err := repo.GetUserByID(c.Context(), userID)
+			if err != nil {
+				return false, domain.ErrInvalidSession
 			}
 
 			if !user.Status {
 				return false, errors.New("invalid user")
 			}
+			user.Expire = !claims.Persistent
 
 			c.Locals(httphelper.LocalUser, user)
+			c.Locals(httphelper.LocalClaims, claims)
 			return true, nil
 		},
 	})
 }
+
+// ApiKey Validates the key of the 'ApiKey' authorization used by machines, its last use is updated at most once per minute.
+func ApiKey(repo domain.ApiKeyRepository) fiber.Handler {
+	return keyauth.New(keyauth.Config{
+		KeyLookup:    "header:" + fiber.HeaderAuthorization,
+		AuthScheme:   domain.ApiKeyScheme,
+		ContextKey:   "token",
+		ErrorHandler: authError,
+		Validator: func(c *fiber.Ctx, key string) (bool, error) {
+			apiKey, err := repo.GetApiKeyByHash(c.Context(), domain.HashToken(key))
+			if err != nil || !apiKey.IsActive() {
+				return false, domain.ErrInvalidApiKey
+			}
+
+			if apiKey.LastUsed == nil || time.Since(*apiKey.LastUsed) > touchInterval {
+				now := time.Now()
+				apiKey.LastUsed = &now
+				_ = repo.TouchApiKey(c.Context(), apiKey)
+			}
+
+			c.Locals(httphelper.LocalApiKey, apiKey)
+			return true, nil
+		},
+	})
+}
+
+// Resource Authenticates the resource routes either by an API key or by an access token.
+func Resource(apiKey, access fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if strings.HasPrefix(c.Get(fiber.HeaderAuthorization), domain.ApiKeyScheme+" ") {
+			return apiKey(c)
+		}
+
+		return access(c)
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+)
+
+const (
+	CookieAccess  string = "msaada_access"
+	CookieRefresh string = "msaada_refresh"
+	CookieCsrf    string = "msaada_csrf"
+	HeaderCsrf    string = "X-CSRF-Token"
+
+	// cookieMaxLife Life of the cookies of the tokens that never expire, the longest kept by the browsers.
+	cookieMaxLife = 400 * 24 * time.Hour
+)
+
+var ErrInvalidCsrfToken = errors.New("invalid csrf token")
+
+// AuthCookies Settings of the cookie mode, nil when the tokens are only sent in the response body.
+var AuthCookies *CookieConfig
+
+// CookieConfig Attributes of the cookies holding the tokens of the browsers. The access and refresh
+// tokens are HttpOnly, the CSRF token is readable by the dashboard and must be sent back in the
+// 'X-CSRF-Token' header of the state-changing requests (double submit).
+type CookieConfig struct {
+	Domain   string
+	Secure   bool
+	SameSite string
+}
+
+// tokenExpiry Returns when the token expires, its signature is checked by the authentication.
+func tokenExpiry(token string) time.Time {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err == nil && claims.ExpiresAt != nil {
+		return claims.ExpiresAt.Time
+	}
+
+	return time.Now().Add(cookieMaxLife)
+}
+
+func (s *CookieConfig) cookie(name, value, path string, expires time.Time, httpOnly bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   s.Domain,
+		Expires:  expires,
+		Secure:   s.Secure,
+		HTTPOnly: httpOnly,
+		SameSite: s.SameSite,
+	}
+}
+
+// Set Moves the tokens of the response to the cookies along with a new CSRF token, responses
+// without an access token, like the ones waiting for the second factor, are left untouched.
+func (s *CookieConfig) Set(c *fiber.Ctx, response *dto.AuthOutputDTO) error {
+	if response.AccessToken == "" {
+		return nil
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	csrf := base64.RawURLEncoding.EncodeToString(random)
+
+	refreshExpiry := tokenExpiry(response.RefreshToken)
+	c.Cookie(s.cookie(CookieAccess, response.AccessToken, "/", tokenExpiry(response.AccessToken), true))
+	c.Cookie(s.cookie(CookieRefresh, response.RefreshToken, "/auth", refreshExpiry, true))
+	c.Cookie(s.cookie(CookieCsrf, csrf, "/", refreshExpiry, false))
+
+	response.AccessToken, response.RefreshToken, response.CsrfToken = "", "", csrf
+	return nil
+}
+
+// Clear Expires the cookies of the tokens.
+func (s *CookieConfig) Clear(c *fiber.Ctx) {
+	expired := time.Unix(0, 0)
+	c.Cookie(s.cookie(CookieAccess, "", "/", expired, true))
+	c.Cookie(s.cookie(CookieRefresh, "", "/auth", expired, true))
+	c.Cookie(s.cookie(CookieCsrf, "", "/", expired, false))
+}
+
+// Auth Authenticates by the token of the cookie when the request has no 'Authorization' header,
+// the state-changing methods must then send the CSRF token matching its cookie.
+func (s *CookieConfig) Auth(name string, next fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Cookies(name)
+		if token == "" || c.Get(fiber.HeaderAuthorization) != "" {
+			return next(c)
+		}
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		default:
+			csrf := c.Cookies(CookieCsrf)
+			if csrf == "" || subtle.ConstantTimeCompare([]byte(csrf), []byte(c.Get(HeaderCsrf))) != 1 {
+				return authError(c, ErrInvalidCsrfToken)
+			}
+		}
+
+		c.Request().Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		return next(c)
+	}
+}
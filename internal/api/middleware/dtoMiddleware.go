@@ -36,3 +36,23 @@ func GetUserDTO(c *fiber.Ctx) error {
 func GetPasswordInputDTO(c *fiber.Ctx) error {
 	return getDTO(c, &dto.PasswordInputDTO{})
 }
+
+func GetTwoFactorDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.TwoFactorInputDTO{})
+}
+
+func GetApiKeyDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.ApiKeyInputDTO{})
+}
+
+func GetChangePasswordDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.ChangePasswordInputDTO{})
+}
+
+func GetRegisterDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.RegisterInputDTO{})
+}
+
+func GetVerifyDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.VerifyInputDTO{})
+}
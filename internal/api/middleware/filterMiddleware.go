@@ -1,33 +1,114 @@
 package middleware
 
 import (
+	"errors"
 	"log"
+	"net/url"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
 	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
 )
 
-func getQuery(c *fiber.Ctx, data interface{}) error {
+// queryFilter Filter of a list, embedding 'filter.Filter'.
+type queryFilter interface {
+	ParseConditions(filter.Fields, url.Values) error
+	SetFieldset(*filter.Fieldset)
+}
+
+func getQuery(c *fiber.Ctx, data queryFilter, fields filter.Fields) error {
+	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 	if err := c.QueryParser(data); err != nil {
 		log.Println(err.Error())
-		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidDatas)
 	}
 
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err == nil {
+		err = data.ParseConditions(fields, query)
+	}
+	if err != nil {
+		if !errors.Is(err, filter.ErrInvalidFilter) {
+			log.Println(err.Error())
+		}
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidFilter)
+	}
+
+	if fieldset, ok := c.Locals(httphelper.LocalFieldset).(*filter.Fieldset); ok {
+		data.SetFieldset(fieldset)
+	}
+
 	c.Locals(httphelper.LocalFilter, data)
 	return c.Next()
 }
 
-func GetGenericFilter(c *fiber.Ctx) error {
-	return getQuery(c, filter.NewFilter())
+// GetFieldset Parses the 'fields' and 'include' of the response with the relations of its resource, before
+// the filter of a list or the item of a detail. The fields not requested are left out of the successful
+// responses.
+func GetFieldset(relations filter.Relations) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+		var include *string
+		if c.Context().QueryArgs().Has("include") {
+			value := c.Query("include")
+			include = &value
+		}
+
+		fieldset, err := filter.ParseFieldset(c.Query("fields"), include, relations)
+		if err != nil {
+			return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidFieldset)
+		}
+
+		c.Locals(httphelper.LocalFieldset, fieldset)
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		body, err := fieldset.Select(c.Response().Body())
+		if err != nil {
+			log.Println(err.Error())
+			return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, messages.ErrGeneric)
+		}
+
+		c.Response().SetBody(body)
+		return nil
+	}
+}
+
+// GetFilter Parses the generic filter of the list with the filter fields of its resource.
+func GetFilter(fields filter.Fields) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return getQuery(c, filter.NewFilter(), fields)
+	}
 }
 
 func GetUserFilter(c *fiber.Ctx) error {
 	return getQuery(c, &filter.UserFilter{
 		Filter:    *filter.NewFilter(),
 		ProfileID: 0,
-	})
+	}, domain.UserFilterFields)
+}
+
+// GetAuditFilter Audit logs are never updated, so they are sorted by creation unless requested otherwise.
+func GetAuditFilter(c *fiber.Ctx) error {
+	audit := filter.NewFilter()
+	audit.Sort = "created_at"
+
+	return getQuery(c, audit, domain.AuditFilterFields)
+}
+
+// GetSearchFilter The global search is in full text, five items of each module by relevance unless requested otherwise.
+func GetSearchFilter(c *fiber.Ctx) error {
+	search := filter.NewFilter()
+	search.SearchMode, search.Sort = filter.SearchFullText, ""
+	search.Page, search.Limit = 1, 5
+
+	return getQuery(c, search, nil)
 }
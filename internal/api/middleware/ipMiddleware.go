@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+// GetRequestIP Keeps the client address, resolved through the trusted proxies, for the layers that only receive the context.
+func GetRequestIP(c *fiber.Ctx) error {
+	c.Locals(httphelper.LocalIP, c.IP())
+	return c.Next()
+}
+
+// ResolveForwardedFor Reduces the 'X-Forwarded-For' of the requests sent by the trusted proxies to its
+// client, read right to left, since Fiber takes the left-most entry, which the client controls.
+func ResolveForwardedFor(trusted httphelper.TrustedProxies) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderXForwardedFor)
+		if header != "" && trusted.Contains(c.Context().RemoteIP().String()) {
+			c.Request().Header.Set(fiber.HeaderXForwardedFor, trusted.ForwardedClient(header))
+		}
+
+		return c.Next()
+	}
+}
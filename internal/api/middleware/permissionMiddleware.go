@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+var methodActions = map[string]string{
+	fiber.MethodGet:    domain.ActionRead,
+	fiber.MethodHead:   domain.ActionRead,
+	fiber.MethodPost:   domain.ActionCreate,
+	fiber.MethodPut:    domain.ActionUpdate,
+	fiber.MethodPatch:  domain.ActionUpdate,
+	fiber.MethodDelete: domain.ActionDelete,
+}
+
+// Allowed Reports whether the API key, or else the authenticated user profile, is granted the action on the module.
+func Allowed(c *fiber.Ctx, module, action string) bool {
+	if apiKey, ok := c.Locals(httphelper.LocalApiKey).(*domain.ApiKey); ok {
+		return apiKey.Allowed(module, action)
+	}
+
+	user, ok := c.Locals(httphelper.LocalUser).(*domain.User)
+	return ok && user.Profile != nil && user.Profile.Allowed(module, action)
+}
+
+// Grantor Returns the rights of the caller, the ones of the API key or else the profile of the
+// authenticated user, nil without either. Nothing beyond them can be granted by the request.
+func Grantor(c *fiber.Ctx) *domain.Profile {
+	if apiKey, ok := c.Locals(httphelper.LocalApiKey).(*domain.ApiKey); ok {
+		return apiKey.Grants()
+	}
+
+	if user, ok := c.Locals(httphelper.LocalUser).(*domain.User); ok {
+		return user.Profile
+	}
+
+	return nil
+}
+
+// CheckPermission Denies the request when the API key or the authenticated user profile is not
+// granted the action matching the request method on the module.
+func CheckPermission(module string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !Allowed(c, module, methodActions[c.Method()]) {
+			messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+			return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, messages.ErrWithoutPermission)
+		}
+
+		return c.Next()
+	}
+}
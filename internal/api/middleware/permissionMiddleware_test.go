@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gofiber/fiber/v2"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+// testMessages Loads the english messages of the responses.
+func testMessages(t *testing.T) *i18n.Translation {
+	bundle := goi18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+
+	_, err := bundle.LoadMessageFile(filepath.Join("..", "..", "..", "configs", "i18n", "active.en.toml"))
+	assert.Nil(t, err)
+
+	return i18n.NewTranslation(goi18n.NewLocalizer(bundle, "en"))
+}
+
+// permissionApp Serves the product and user modules behind their permission check, 'caller' stores
+// the credentials of the request as the authentication middlewares do.
+func permissionApp(t *testing.T, caller func(*fiber.Ctx)) *fiber.App {
+	messages := testMessages(t)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(httphelper.LocalLang, messages)
+		caller(c)
+		return c.Next()
+	})
+
+	ok := func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	app.All("/product", CheckPermission(domain.ModuleProduct), ok)
+	app.All("/user", CheckPermission(domain.ModuleUser), ok)
+
+	return app
+}
+
+// productEditor Profile reading and updating the products, without any other permission.
+func productEditor() *domain.Profile {
+	return &domain.Profile{
+		Name:        "EDITOR",
+		Permissions: []domain.Permission{{Module: domain.ModuleProduct, Read: true, Update: true}},
+	}
+}
+
+func status(t *testing.T, app *fiber.App, method, path string) int {
+	resp, err := app.Test(httptest.NewRequest(method, path, nil))
+	assert.Nil(t, err)
+
+	return resp.StatusCode
+}
+
+// go test -run TestCheckPermissionUser
+func TestCheckPermissionUser(t *testing.T) {
+	app := permissionApp(t, func(c *fiber.Ctx) {
+		c.Locals(httphelper.LocalUser, &domain.User{Profile: productEditor()})
+	})
+
+	for method, expected := range map[string]int{
+		fiber.MethodGet:    fiber.StatusNoContent,
+		fiber.MethodHead:   fiber.StatusNoContent,
+		fiber.MethodPost:   fiber.StatusForbidden,
+		fiber.MethodPut:    fiber.StatusNoContent,
+		fiber.MethodPatch:  fiber.StatusNoContent,
+		fiber.MethodDelete: fiber.StatusForbidden,
+	} {
+		assert.Equal(t, expected, status(t, app, method, "/product"), method)
+	}
+
+	// The permissions of a module grant nothing on the others.
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, fiber.MethodGet, "/user"))
+}
+
+// go test -run TestCheckPermissionApiKey
+func TestCheckPermissionApiKey(t *testing.T) {
+	app := permissionApp(t, func(c *fiber.Ctx) {
+		c.Locals(httphelper.LocalApiKey, &domain.ApiKey{Profile: productEditor(), Scopes: []string{"product:read"}})
+		// The key is checked in place of the user.
+		c.Locals(httphelper.LocalUser, &domain.User{Profile: productEditor()})
+	})
+
+	// The scopes limit the profile of the key.
+	assert.Equal(t, fiber.StatusNoContent, status(t, app, fiber.MethodGet, "/product"))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, fiber.MethodPut, "/product"))
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, fiber.MethodGet, "/user"))
+}
+
+// go test -run TestCheckPermissionAnonymous
+func TestCheckPermissionAnonymous(t *testing.T) {
+	app := permissionApp(t, func(c *fiber.Ctx) {})
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, fiber.MethodGet, "/product"))
+
+	app = permissionApp(t, func(c *fiber.Ctx) {
+		c.Locals(httphelper.LocalUser, &domain.User{})
+	})
+	assert.Equal(t, fiber.StatusForbidden, status(t, app, fiber.MethodGet, "/product"))
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/ratelimit"
+)
+
+var (
+	// MidRateLimit Limit of the resource routes, placed after their authentication.
+	MidRateLimit fiber.Handler
+	// MidAuthRateLimit Stricter limit of the routes checking credentials or sending mails, by address.
+	MidAuthRateLimit fiber.Handler
+)
+
+// RateLimitKey Returns the key whose requests are counted together.
+type RateLimitKey func(c *fiber.Ctx) string
+
+// RateLimitByIP Counts the requests of the client address.
+func RateLimitByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// RateLimitByClient Counts the requests of the API key, or else of the authenticated user, so the
+// users sharing an address do not share a limit. Anonymous requests are counted by address.
+func RateLimitByClient(c *fiber.Ctx) string {
+	if apiKey, ok := c.Locals(httphelper.LocalApiKey).(*domain.ApiKey); ok {
+		return fmt.Sprintf("apikey:%d", apiKey.Id)
+	}
+
+	if user, ok := c.Locals(httphelper.LocalUser).(*domain.User); ok {
+		return fmt.Sprintf("user:%d", user.Id)
+	}
+
+	return RateLimitByIP(c)
+}
+
+// RateLimit Applies the limiter to the requests grouped by the key, reporting the state of the window
+// in the 'RateLimit-*' headers. The requests are let through when the store fails.
+func RateLimit(limiter *ratelimit.Limiter, key RateLimitKey) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := limiter.Allow(c.Context(), key(c))
+		if err != nil {
+			log.Printf("rate limit '%v' let the request through, the store failed: %v", limiter.Policy().Name, err)
+			return c.Next()
+		}
+
+		reset := strconv.Itoa(int(time.Until(result.Reset).Seconds()) + 1)
+		c.Set("RateLimit-Policy", limiter.Policy().String())
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", reset)
+
+		if !result.Allowed {
+			messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+			c.Set(fiber.HeaderRetryAfter, reset)
+			return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrManyRequest)
+		}
+
+		return c.Next()
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
 	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
 )
 
@@ -46,13 +47,17 @@ func (s *RequesttMiddleware) handlerDBError(c *fiber.Ctx, err error, item string
 			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, translation.ErrProfileNotFound)
 		case domain.ProductTableName:
 			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, translation.ErrProductNotFound)
+		case domain.ApiKeyTableName:
+			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, translation.ErrApiKeyNotFound)
 		}
 	}
 
 	return s.handlerError(c, err, translation)
 }
 
-func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, item interface{}, itemType string, preload ...string) error {
+// itemByID Loads the item of the route with the relations included by the fieldset of the request, or
+// with the given preloads on the routes without a fieldset.
+func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, item interface{}, itemType string, relations filter.Relations, preload ...string) error {
 	id, err := c.ParamsInt(httphelper.ParamID, 0)
 	if err != nil || id < 1 {
 		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
@@ -60,8 +65,12 @@ func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, item interface{}, itemType s
 	}
 
 	db := s.postgres.WithContext(c.Context())
-	for _, pre := range preload {
-		db = db.Preload(pre)
+	if fieldset, ok := c.Locals(httphelper.LocalFieldset).(*filter.Fieldset); ok {
+		db = fieldset.Preload(db, relations)
+	} else {
+		for _, pre := range preload {
+			db = db.Preload(pre)
+		}
 	}
 
 	if err := db.First(item, id).Error; err != nil {
@@ -73,13 +82,17 @@ func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, item interface{}, itemType s
 }
 
 func (s *RequesttMiddleware) ProfileByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Profile{}, domain.ProfileTableName, clause.Associations)
+	return s.itemByID(c, &domain.Profile{}, domain.ProfileTableName, domain.ProfileRelations, clause.Associations)
 }
 
 func (s *RequesttMiddleware) UserByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.User{}, domain.UserTableName, postgre.ProfilePermission)
+	return s.itemByID(c, &domain.User{}, domain.UserTableName, domain.UserRelations, postgre.ProfilePermission)
 }
 
 func (s *RequesttMiddleware) ProductByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Product{}, domain.ProductTableName)
+	return s.itemByID(c, &domain.Product{}, domain.ProductTableName, nil)
+}
+
+func (s *RequesttMiddleware) ApiKeyByID(c *fiber.Ctx) error {
+	return s.itemByID(c, &domain.ApiKey{}, domain.ApiKeyTableName, domain.ApiKeyRelations, postgre.Profile)
 }
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+)
+
+func NewApiKeyService(r domain.ApiKeyRepository, pr domain.ProfileRepository) domain.ApiKeyService {
+	return &apiKeyService{
+		apiKeyRepository:  r,
+		profileRepository: pr,
+	}
+}
+
+type apiKeyService struct {
+	apiKeyRepository  domain.ApiKeyRepository
+	profileRepository domain.ProfileRepository
+}
+
+// checkGrant Rejects the key when its profile or scopes grant more than the caller holds.
+func (s *apiKeyService) checkGrant(ctx context.Context, caller *domain.User, apiKey *domain.ApiKey) error {
+	profile, err := s.profileRepository.GetProfileByID(ctx, apiKey.ProfileID)
+	if err != nil {
+		return err
+	}
+
+	apiKey.Profile = profile
+	if caller == nil || !apiKey.GrantableBy(caller) {
+		return domain.ErrApiKeyForbidden
+	}
+
+	return nil
+}
+
+func (s *apiKeyService) generateApiKeyOutputDTO(apiKey *domain.ApiKey) *dto.ApiKeyOutputDTO {
+	output := &dto.ApiKeyOutputDTO{
+		Id:        apiKey.Id,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		Status:    apiKey.Status,
+		ExpiresAt: apiKey.ExpiresAt,
+		LastUsed:  apiKey.LastUsed,
+		CreatedAt: apiKey.CreatedAt,
+		Profile: dto.ProfileOutputDTO{
+			Id: apiKey.ProfileID,
+		},
+	}
+
+	if apiKey.Profile != nil {
+		output.Profile.Name = apiKey.Profile.Name
+	}
+
+	return output
+}
+
+// GetApiKeyByID Implementation of 'GetApiKeyByID'.
+func (s *apiKeyService) GetApiKeyByID(ctx context.Context, apiKeyID uint) (*dto.ApiKeyOutputDTO, error) {
+	apiKey, err := s.apiKeyRepository.GetApiKeyByID(ctx, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generateApiKeyOutputDTO(apiKey), nil
+}
+
+// GetApiKeys Implementation of 'GetApiKeys'.
+func (s *apiKeyService) GetApiKeys(ctx context.Context, filter *filter.Filter) (*dto.ListItemsOutputDTO, error) {
+	var count *int64
+	if filter.WithCount() {
+		total, err := s.apiKeyRepository.CountApiKeys(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
+	}
+
+	apiKeys, err := s.apiKeyRepository.GetApiKeys(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	outputApiKeys := &[]dto.ApiKeyOutputDTO{}
+	for _, apiKey := range *apiKeys {
+		*outputApiKeys = append(*outputApiKeys, *s.generateApiKeyOutputDTO(&apiKey))
+	}
+
+	return &dto.ListItemsOutputDTO{
+		Count:      count,
+		Items:      outputApiKeys,
+		NextCursor: filter.NextCursor,
+		PrevCursor: filter.PrevCursor,
+	}, nil
+}
+
+// CreateApiKey Implementation of 'CreateApiKey', the key is only returned here.
+func (s *apiKeyService) CreateApiKey(ctx context.Context, caller *domain.User, data *dto.ApiKeyInputDTO) (*dto.ApiKeyOutputDTO, error) {
+	candidate := &domain.ApiKey{}
+	if err := candidate.Bind(data); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(ctx, caller, candidate); err != nil {
+		return nil, err
+	}
+
+	apiKey, raw, err := s.apiKeyRepository.CreateApiKey(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err = s.apiKeyRepository.GetApiKeyByID(ctx, apiKey.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	output := s.generateApiKeyOutputDTO(apiKey)
+	output.Key = raw
+	return output, nil
+}
+
+// UpdateApiKey Implementation of 'UpdateApiKey'.
+func (s *apiKeyService) UpdateApiKey(ctx context.Context, caller *domain.User, apiKey *domain.ApiKey, data *dto.ApiKeyInputDTO) (*dto.ApiKeyOutputDTO, error) {
+	candidate := *apiKey
+	if err := candidate.Bind(data); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(ctx, caller, &candidate); err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepository.UpdateApiKey(ctx, apiKey, data); err != nil {
+		return nil, err
+	}
+
+	apiKey, err := s.apiKeyRepository.GetApiKeyByID(ctx, apiKey.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generateApiKeyOutputDTO(apiKey), nil
+}
+
+// DeleteApiKey Implementation of 'DeleteApiKey'.
+func (s *apiKeyService) DeleteApiKey(ctx context.Context, apiKey *domain.ApiKey) error {
+	return s.apiKeyRepository.DeleteApiKey(ctx, apiKey)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+)
+
+func NewAuditService(r domain.AuditRepository) domain.AuditService {
+	return &auditService{
+		auditRepository: r,
+	}
+}
+
+type auditService struct {
+	auditRepository domain.AuditRepository
+}
+
+func (s *auditService) generateAuditLogOutputDTO(log *domain.AuditLog) *dto.AuditLogOutputDTO {
+	diff := map[string]dto.AuditChangeOutputDTO{}
+	for column, change := range log.Diff {
+		diff[column] = dto.AuditChangeOutputDTO{Before: change.Before, After: change.After}
+	}
+
+	return &dto.AuditLogOutputDTO{
+		Id:             log.Id,
+		ActorID:        log.ActorID,
+		ApiKeyID:       log.ApiKeyID,
+		ImpersonatedID: log.ImpersonatedID,
+		IP:             log.IP,
+		RequestID:      log.RequestID,
+		Action:         log.Action,
+		Entity:         log.Entity,
+		EntityID:       log.EntityID,
+		Diff:           diff,
+		CreatedAt:      log.CreatedAt,
+	}
+}
+
+// GetAuditLogs Implementation of 'GetAuditLogs'.
+func (s *auditService) GetAuditLogs(ctx context.Context, filter *filter.Filter) (*dto.ListItemsOutputDTO, error) {
+	var count *int64
+	if filter.WithCount() {
+		total, err := s.auditRepository.CountAuditLogs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
+	}
+
+	logs, err := s.auditRepository.GetAuditLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	outputLogs := &[]dto.AuditLogOutputDTO{}
+	for _, log := range *logs {
+		*outputLogs = append(*outputLogs, *s.generateAuditLogOutputDTO(&log))
+	}
+
+	return &dto.ListItemsOutputDTO{
+		Count:      count,
+		Items:      outputLogs,
+		NextCursor: filter.NextCursor,
+		PrevCursor: filter.PrevCursor,
+	}, nil
+}
@@ -3,21 +3,137 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
 	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/oidc"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/totp"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 )
 
-func NewAuthService(r domain.UserRepository) domain.AuthService {
+// defaultTotpIssuer Issuer shown by the authenticator apps when 'TOTP_ISSUER' is not set.
+const defaultTotpIssuer = "MSAADA"
+
+const (
+	defaultLoginMailAttempts = 5
+	defaultLoginIPAttempts   = 20
+	defaultLoginWindow       = 15 * time.Minute
+	defaultLoginLock         = time.Minute
+	defaultLoginMaxLock      = time.Hour
+)
+
+// NewAuthService Creates the authentication service, 'provider' is nil when the OIDC login is disabled.
+func NewAuthService(r domain.UserRepository, pr domain.ProfileRepository, sr domain.SessionRepository, tr domain.TwoFactorRepository, ar domain.AttemptRepository, or domain.OidcStateRepository, aur domain.AuditRepository, access, refresh domain.TokenSigner, provider *oidc.Provider) domain.AuthService {
 	return &authService{
-		userRepository: r,
+		accessSigner:        access,
+		refreshSigner:       refresh,
+		oidcProvider:        provider,
+		oidcProfile:         os.Getenv("OIDC_DEFAULT_PROFILE"),
+		userRepository:      r,
+		profileRepository:   pr,
+		sessionRepository:   sr,
+		twoFactorRepository: tr,
+		attemptRepository:   ar,
+		oidcStateRepository: or,
+		auditRepository:     aur,
+		passwords:           newPasswordValidator(r),
+		mailPolicy:          loginPolicy("LOGIN_MAX_ATTEMPTS", defaultLoginMailAttempts),
+		ipPolicy:            loginPolicy("LOGIN_IP_MAX_ATTEMPTS", defaultLoginIPAttempts),
 	}
 }
 
 type authService struct {
-	userRepository domain.UserRepository
+	accessSigner        domain.TokenSigner
+	refreshSigner       domain.TokenSigner
+	oidcProvider        *oidc.Provider
+	oidcProfile         string
+	userRepository      domain.UserRepository
+	profileRepository   domain.ProfileRepository
+	sessionRepository   domain.SessionRepository
+	twoFactorRepository domain.TwoFactorRepository
+	attemptRepository   domain.AttemptRepository
+	oidcStateRepository domain.OidcStateRepository
+	auditRepository     domain.AuditRepository
+	passwords           *passwordValidator
+	mailPolicy          *domain.AttemptPolicy
+	ipPolicy            *domain.AttemptPolicy
+}
+
+// loginPolicy Reads the login attempts policy, the limit from 'limitEnv' and the times, in minutes,
+// from 'LOGIN_ATTEMPT_WINDOW', 'LOGIN_LOCK_TIME' and 'LOGIN_LOCK_MAX'.
+func loginPolicy(limitEnv string, limit int) *domain.AttemptPolicy {
+	policy := &domain.AttemptPolicy{
+		Limit:   envInt(limitEnv, limit),
+		Window:  defaultLoginWindow,
+		Lock:    defaultLoginLock,
+		MaxLock: defaultLoginMaxLock,
+	}
+
+	for env, value := range map[string]*time.Duration{
+		"LOGIN_ATTEMPT_WINDOW": &policy.Window,
+		"LOGIN_LOCK_TIME":      &policy.Lock,
+		"LOGIN_LOCK_MAX":       &policy.MaxLock,
+	} {
+		if configured, err := helpers.DurationFromString(os.Getenv(env), time.Minute); err == nil {
+			*value = configured
+		}
+	}
+
+	return policy
+}
+
+// checkAttempts Refuses the login while the account or the address is locked.
+func (s *authService) checkAttempts(ctx context.Context, mail, ip string) error {
+	return checkLocks(ctx, s.attemptRepository, domain.LoginMailKey(mail), domain.LoginIPKey(ip))
+}
+
+// checkLocks Returns the lockout error of the first locked key.
+func checkLocks(ctx context.Context, repo domain.AttemptRepository, keys ...string) error {
+	for _, key := range keys {
+		attempt, err := repo.GetAttempt(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if err := attempt.Locked(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// auditLogin Records a login of the user, or of the unknown mail when the user ID is zero.
+func (s *authService) auditLogin(ctx context.Context, action string, userID uint, mail, ip string) {
+	entry := domain.NewAuditLog(ctx, action, domain.UserTableName, userID, nil, map[string]interface{}{"mail": mail})
+	entry.IP = ip
+	if action == domain.AuditLogin {
+		entry.ActorID = &userID
+	}
+
+	if err := s.auditRepository.CreateAuditLog(ctx, entry); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// failAttempt Counts a failed login for the account and the address.
+func (s *authService) failAttempt(ctx context.Context, userID uint, mail, ip string) {
+	s.auditLogin(ctx, domain.AuditLoginFailed, userID, mail, ip)
+
+	if _, err := s.attemptRepository.RegisterAttempt(ctx, domain.LoginMailKey(mail), s.mailPolicy); err != nil {
+		log.Println(err.Error())
+	}
+
+	if _, err := s.attemptRepository.RegisterAttempt(ctx, domain.LoginIPKey(ip), s.ipPolicy); err != nil {
+		log.Println(err.Error())
+	}
 }
 
 func (s *authService) generateUserOutputDTO(user *domain.User) *dto.UserOutputDTO {
@@ -26,31 +142,30 @@ func (s *authService) generateUserOutputDTO(user *domain.User) *dto.UserOutputDT
 	}
 
 	return &dto.UserOutputDTO{
-		Id:     user.Id,
-		Name:   user.Name,
-		Email:  user.Email,
-		Status: user.Status,
+		Id:       user.Id,
+		Name:     user.Name,
+		Email:    user.Email,
+		Status:   user.Status,
+		Verified: !user.VerifyPending,
 		Profile: dto.ProfileOutputDTO{
-			Id:   user.ProfileID,
-			Name: user.Profile.Name,
-			Permissions: &dto.PermissionsOutputDTO{
-				UserModule:    user.Profile.Permissions.UserModule,
-				ProfileModule: user.Profile.Permissions.ProfileModule,
-				ProductModule: user.Profile.Permissions.ProductModule,
-			},
+			Id:          user.ProfileID,
+			Name:        user.Profile.Name,
+			TwoFactor:   &user.Profile.TwoFactor,
+			Permissions: generatePermissionsOutputDTO(user.Profile),
 		},
 	}
 }
 
-func (s *authService) generateAuthOutputDTO(user *domain.User, ip string) *dto.AuthOutputDTO {
-	accessTime, refreshTime := "-", "-"
-	if user.Expire {
-		accessTime = os.Getenv("ACCESS_TOKEN_EXPIRE")
-		refreshTime = os.Getenv("RFRESH_TOKEN_EXPIRE")
+func (s *authService) generateAuthOutputDTO(user *domain.User, session *domain.Session, ip string) *dto.AuthOutputDTO {
+	// The refresh tokens of the sessions that never expire do not expire either, their key is kept
+	// while the session references it.
+	var refreshLife time.Duration
+	if session.Expire {
+		refreshLife, _ = helpers.DurationFromString(os.Getenv("RFRESH_TOKEN_EXPIRE"), time.Minute)
 	}
 
-	accessToken, _ := user.GenerateToken(accessTime, os.Getenv("ACCESS_TOKEN_PRIVAT"), ip)
-	refreshToken, _ := user.GenerateToken(refreshTime, os.Getenv("RFRESH_TOKEN_PRIVAT"), ip)
+	accessToken, _ := user.GenerateToken(s.accessSigner, domain.TokenAccess, domain.AccessTokenLife(), ip, session.Token, uuid.New().String())
+	refreshToken, _ := user.GenerateToken(s.refreshSigner, domain.TokenRefresh, refreshLife, ip, session.Token, session.RefreshID)
 
 	return &dto.AuthOutputDTO{
 		User:         s.generateUserOutputDTO(user),
@@ -59,13 +174,51 @@ func (s *authService) generateAuthOutputDTO(user *domain.User, ip string) *dto.A
 	}
 }
 
-func (s *authService) Login(ctx context.Context, credentials *dto.AuthInputDTO, ip string) (*dto.AuthOutputDTO, error) {
+// sessionExpiration Returns when a session stops being refreshable, nil for sessions that never expire.
+func (s *authService) sessionExpiration(expire bool) *time.Time {
+	if !expire {
+		return nil
+	}
+
+	life, err := helpers.DurationFromString(os.Getenv("RFRESH_TOKEN_EXPIRE"), time.Minute)
+	if err != nil {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(life)
+	return &expiresAt
+}
+
+func (s *authService) createSession(ctx context.Context, user *domain.User, expire bool, ip, agent string) (*domain.Session, error) {
+	session := &domain.Session{
+		UserID:       user.Id,
+		User:         user,
+		Token:        uuid.New().String(),
+		RefreshID:    uuid.New().String(),
+		IP:           ip,
+		Agent:        agent,
+		Expire:       expire,
+		ExpiresAt:    s.sessionExpiration(expire),
+		LastSeen:     time.Now(),
+		RefreshKeyID: s.refreshSigner.KeyID(),
+	}
+
+	return session, s.sessionRepository.CreateSession(ctx, session)
+}
+
+func (s *authService) Login(ctx context.Context, credentials *dto.AuthInputDTO, ip, agent string) (*dto.AuthOutputDTO, error) {
+	if err := s.checkAttempts(ctx, credentials.Login, ip); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepository.GetUserByMail(ctx, credentials.Login)
 	if err != nil {
+		s.failAttempt(ctx, 0, credentials.Login, ip)
 		return nil, err
 	}
 
 	if !user.ValidatePassword(credentials.Password) {
+		s.failAttempt(ctx, user.Id, credentials.Login, ip)
 		return nil, errors.New("invalid password")
 	}
 
@@ -73,14 +226,355 @@ func (s *authService) Login(ctx context.Context, credentials *dto.AuthInputDTO,
 		return nil, errors.New("invalid user")
 	}
 
-	user.Expire = credentials.Expire
-	return s.generateAuthOutputDTO(user, ip), nil
+	// The hashes made before a change of the hasher settings are only replaced when the password is known.
+	if user.PasswordNeedsRehash() {
+		if err := s.userRepository.RehashUserPassword(ctx, user, credentials.Password); err != nil {
+			log.Println(err.Error())
+		}
+	}
+
+	return s.completeLogin(ctx, user, credentials.Expire, ip, agent)
+}
+
+// completeLogin Opens the session of an authenticated user, or asks for the second factor when it is required.
+func (s *authService) completeLogin(ctx context.Context, user *domain.User, expire bool, ip, agent string) (*dto.AuthOutputDTO, error) {
+	user.Expire = expire
+	if user.TwoFactorRequired() {
+		token, err := user.GenerateTwoFactorToken(s.accessSigner, ip)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.AuthOutputDTO{
+			TwoFactorToken: token,
+			TwoFactorSetup: !user.TotpEnabled,
+		}, nil
+	}
+
+	session, err := s.createSession(ctx, user, expire, ip, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogin(ctx, domain.AuditLogin, user.Id, user.Email, ip)
+	_ = s.attemptRepository.ResetAttempts(ctx, domain.LoginMailKey(user.Email))
+	return s.generateAuthOutputDTO(user, session, ip), nil
+}
+
+// OidcLogin Starts a login at the identity provider, returning the address the user is sent to.
+func (s *authService) OidcLogin(ctx context.Context, expire bool) (string, error) {
+	if s.oidcProvider == nil {
+		return "", domain.ErrOidcDisabled
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := oidc.RandomString()
+		if err != nil {
+			return "", err
+		}
+		values[i] = value
+	}
+
+	state, nonce, verifier := values[0], values[1], values[2]
+	if err := s.oidcStateRepository.CreateOidcState(ctx, &domain.OidcState{
+		Hash:      domain.HashToken(state),
+		Nonce:     nonce,
+		Verifier:  verifier,
+		Expire:    expire,
+		ExpiresAt: time.Now().Add(domain.OidcStateLife),
+	}); err != nil {
+		return "", err
+	}
+
+	return s.oidcProvider.AuthCodeURL(ctx, state, nonce, verifier)
+}
+
+// OidcCallback Completes the login with the code returned by the identity provider, the verified
+// email is matched to a user, which is created in 'OIDC_DEFAULT_PROFILE' when it is configured.
+func (s *authService) OidcCallback(ctx context.Context, code, state, ip, agent string) (*dto.AuthOutputDTO, error) {
+	if s.oidcProvider == nil {
+		return nil, domain.ErrOidcDisabled
+	}
+
+	login, err := s.oidcStateRepository.UseOidcState(ctx, domain.HashToken(state))
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := s.oidcProvider.Exchange(ctx, code, login.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.oidcProvider.Verify(ctx, idToken, login.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ErrOidcEmailNotVerified
+	}
+
+	user, err := s.oidcUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.Status {
+		return nil, errors.New("invalid user")
+	}
+
+	return s.completeLogin(ctx, user, login.Expire, ip, agent)
+}
+
+func (s *authService) oidcUser(ctx context.Context, claims *oidc.Claims) (*domain.User, error) {
+	user, err := s.userRepository.GetUserByMail(ctx, claims.Email)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) || s.oidcProfile == "" {
+		return user, err
+	}
+
+	profile, err := s.profileRepository.GetProfileByName(ctx, s.oidcProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name is required to have at least five characters.
+	name, status := claims.Name, true
+	if len(name) < 5 {
+		name = claims.Email
+	}
+
+	created, err := s.userRepository.CreateUser(ctx, &dto.UserInputDTO{
+		Name:      &name,
+		Email:     &claims.Email,
+		Status:    &status,
+		ProfileID: &profile.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(ctx, created.Id)
 }
 
 func (s *authService) Me(user *domain.User) *dto.UserOutputDTO {
 	return s.generateUserOutputDTO(user)
 }
 
-func (s *authService) Refresh(user *domain.User, ip string) *dto.AuthOutputDTO {
-	return s.generateAuthOutputDTO(user, ip)
+// Refresh Rotates the session refresh ID, a concurrent rotation of the same refresh token revokes the session.
+func (s *authService) Refresh(ctx context.Context, session *domain.Session, ip string) (*dto.AuthOutputDTO, error) {
+	refreshID := session.RefreshID
+	session.RefreshID = uuid.New().String()
+	session.IP = ip
+	session.LastSeen = time.Now()
+	session.ExpiresAt = s.sessionExpiration(session.Expire)
+	session.RefreshKeyID = s.refreshSigner.KeyID()
+
+	if err := s.sessionRepository.RotateSession(ctx, session, refreshID); err != nil {
+		if errors.Is(err, domain.ErrTokenReused) {
+			_ = s.sessionRepository.RevokeSession(ctx, session)
+		}
+		return nil, err
+	}
+
+	return s.generateAuthOutputDTO(session.User, session, ip), nil
+}
+
+// validateTwoFactor Checks the TOTP code or, when informed, consumes one of the recovery codes. The
+// step of an accepted TOTP code is stored, so the code can not be replayed within its window.
+func (s *authService) validateTwoFactor(ctx context.Context, user *domain.User, input *dto.TwoFactorInputDTO) error {
+	if user.TotpSecret == nil {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	if input.RecoveryCode != "" && user.TotpEnabled {
+		return s.twoFactorRepository.UseRecoveryCode(ctx, user.Id, domain.HashToken(domain.NormalizeRecoveryCode(input.RecoveryCode)))
+	}
+
+	step, ok := totp.Match(*user.TotpSecret, input.Code, time.Now())
+	if !ok || step <= user.TotpStep {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	return s.twoFactorRepository.UseTotpStep(ctx, user, step)
+}
+
+// SetupTwoFactor Implementation of 'SetupTwoFactor'.
+func (s *authService) SetupTwoFactor(ctx context.Context, user *domain.User) (*dto.TwoFactorSetupOutputDTO, error) {
+	if user.TotpEnabled {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.SetTwoFactorSecret(ctx, user, secret); err != nil {
+		return nil, err
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = defaultTotpIssuer
+	}
+
+	return &dto.TwoFactorSetupOutputDTO{
+		Secret: secret,
+		URI:    totp.URI(issuer, user.Email, secret),
+	}, nil
+}
+
+// EnableTwoFactor Implementation of 'EnableTwoFactor'.
+func (s *authService) EnableTwoFactor(ctx context.Context, user *domain.User, input *dto.TwoFactorInputDTO) (*dto.RecoveryCodesOutputDTO, error) {
+	if user.TotpEnabled {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	if err := s.validateTwoFactor(ctx, user, input); err != nil {
+		return nil, err
+	}
+
+	codes, raws, err := domain.NewRecoveryCodes(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.EnableTwoFactor(ctx, user, codes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesOutputDTO{RecoveryCodes: raws}, nil
+}
+
+// DisableTwoFactor Implementation of 'DisableTwoFactor'.
+func (s *authService) DisableTwoFactor(ctx context.Context, user *domain.User, input *dto.TwoFactorInputDTO) error {
+	if user.Profile != nil && user.Profile.TwoFactor {
+		return domain.ErrTwoFactorRequired
+	}
+
+	if err := s.validateTwoFactor(ctx, user, input); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepository.DisableTwoFactor(ctx, user)
+}
+
+// VerifyTwoFactor Completes a login waiting for the second factor, enabling it first when it was being enrolled.
+// The two-factor token is consumed by the login, so it opens a single session.
+func (s *authService) VerifyTwoFactor(ctx context.Context, user *domain.User, claims *domain.Claims, input *dto.TwoFactorInputDTO, ip, agent string) (*dto.AuthOutputDTO, error) {
+	if err := s.checkAttempts(ctx, user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if !user.TotpEnabled {
+		codes, err := s.EnableTwoFactor(ctx, user, input)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+				s.failAttempt(ctx, user.Id, user.Email, ip)
+			}
+			return nil, err
+		}
+		recoveryCodes = codes.RecoveryCodes
+	} else if err := s.validateTwoFactor(ctx, user, input); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			s.failAttempt(ctx, user.Id, user.Email, ip)
+		}
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.UseTwoFactorToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+
+	session, err := s.createSession(ctx, user, user.Expire, ip, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogin(ctx, domain.AuditLogin, user.Id, user.Email, ip)
+	_ = s.attemptRepository.ResetAttempts(ctx, domain.LoginMailKey(user.Email))
+
+	output := s.generateAuthOutputDTO(user, session, ip)
+	output.RecoveryCodes = recoveryCodes
+	return output, nil
+}
+
+// ChangePassword Implementation of 'ChangePassword', every session of the user is revoked and a new
+// one replaces the current session. Wrong current passwords count as failed logins.
+func (s *authService) ChangePassword(ctx context.Context, user *domain.User, session *domain.Session, data *dto.ChangePasswordInputDTO, ip, agent string) (*dto.AuthOutputDTO, error) {
+	if err := s.checkAttempts(ctx, user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	if !user.ValidatePassword(*data.CurrentPassword) {
+		s.failAttempt(ctx, user.Id, user.Email, ip)
+		return nil, errors.New("invalid password")
+	}
+
+	if err := s.passwords.validate(ctx, user, *data.Password); err != nil {
+		return nil, err
+	}
+
+	pass := &dto.PasswordInputDTO{Password: data.Password, PasswordConfirm: data.PasswordConfirm}
+	if err := s.userRepository.SetUserPassword(ctx, user, pass); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepository.RevokeUserSessions(ctx, user.Id); err != nil {
+		return nil, err
+	}
+
+	user.Expire = session.Expire
+	newSession, err := s.createSession(ctx, user, session.Expire, ip, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generateAuthOutputDTO(user, newSession, ip), nil
+}
+
+// Impersonate Implementation of 'Impersonate', opens a short-lived session of the user for the admin,
+// only its access token is issued so it ends with the session.
+func (s *authService) Impersonate(ctx context.Context, admin, user *domain.User, ip, agent string) (*dto.AuthOutputDTO, error) {
+	if !admin.CanImpersonate(user) {
+		return nil, domain.ErrImpersonationForbidden
+	}
+
+	expiresAt := time.Now().Add(domain.ImpersonationLife())
+	session := &domain.Session{
+		UserID:         user.Id,
+		User:           user,
+		Token:          uuid.New().String(),
+		RefreshID:      uuid.New().String(),
+		IP:             ip,
+		Agent:          agent,
+		Expire:         true,
+		ExpiresAt:      &expiresAt,
+		LastSeen:       time.Now(),
+		ImpersonatorID: &admin.Id,
+	}
+
+	if err := s.sessionRepository.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	entry := domain.NewAuditLog(ctx, domain.AuditImpersonate, domain.UserTableName, user.Id, nil, map[string]interface{}{"expires_at": expiresAt})
+	if err := s.auditRepository.CreateAuditLog(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	token, err := user.GenerateImpersonationToken(s.accessSigner, session, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	output := s.generateUserOutputDTO(user)
+	output.ImpersonatorID = &admin.Id
+	return &dto.AuthOutputDTO{
+		User:        output,
+		AccessToken: token,
+	}, nil
 }
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"os"
+	"strconv"
+)
+
+// envInt Reads an integer setting, returning 'value' when it is unset or invalid.
+func envInt(env string, value int) int {
+	if configured, err := strconv.Atoi(os.Getenv(env)); err == nil {
+		return configured
+	}
+
+	return value
+}
+
+// envBool Reads a boolean setting, returning 'value' when it is unset or invalid.
+func envBool(env string, value bool) bool {
+	if configured, err := strconv.ParseBool(os.Getenv(env)); err == nil {
+		return configured
+	}
+
+	return value
+}
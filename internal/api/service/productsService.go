@@ -20,8 +20,9 @@ type productService struct {
 
 func (s *productService) generateProductOutputDTO(product *domain.Product) *dto.ProductOutputDTO {
 	return &dto.ProductOutputDTO{
-		Id:   product.Id,
-		Name: product.Name,
+		Id:       product.Id,
+		Name:     product.Name,
+		Headline: product.Headline,
 	}
 }
 
@@ -37,9 +38,13 @@ func (s *productService) GetProductByID(ctx context.Context, productID uint) (*d
 
 // GetProducts Implementation of 'GetProducts'.
 func (s *productService) GetProducts(ctx context.Context, filter *filter.Filter) (*dto.ListItemsOutputDTO, error) {
-	count, err := s.productRepository.CountProducts(ctx, filter)
-	if err != nil {
-		return nil, err
+	var count *int64
+	if filter.WithCount() {
+		total, err := s.productRepository.CountProducts(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
 	}
 
 	products, err := s.productRepository.GetProducts(ctx, filter)
@@ -53,8 +58,10 @@ func (s *productService) GetProducts(ctx context.Context, filter *filter.Filter)
 	}
 
 	return &dto.ListItemsOutputDTO{
-		Count: count,
-		Items: outputProducts,
+		Count:      count,
+		Items:      outputProducts,
+		NextCursor: filter.NextCursor,
+		PrevCursor: filter.PrevCursor,
 	}, nil
 }
 
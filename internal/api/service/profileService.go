@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"slices"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
@@ -18,18 +19,39 @@ type profileService struct {
 	profileRepository domain.ProfileRepository
 }
 
+func generatePermissionsOutputDTO(profile *domain.Profile) dto.PermissionsOutputDTO {
+	if profile.Permissions == nil {
+		return nil
+	}
+
+	permissions := dto.PermissionsOutputDTO{}
+	for _, module := range domain.Modules {
+		permissions[module] = dto.GrantOutputDTO{
+			Read:   profile.Allowed(module, domain.ActionRead),
+			Create: profile.Allowed(module, domain.ActionCreate),
+			Update: profile.Allowed(module, domain.ActionUpdate),
+			Delete: profile.Allowed(module, domain.ActionDelete),
+		}
+	}
+
+	return permissions
+}
+
 func (s *profileService) generateProfileOutputDTO(profile *domain.Profile) *dto.ProfileOutputDTO {
 	return &dto.ProfileOutputDTO{
-		Id:   profile.Id,
-		Name: profile.Name,
-		Permissions: &dto.PermissionsOutputDTO{
-			UserModule:    profile.Permissions.UserModule,
-			ProfileModule: profile.Permissions.ProfileModule,
-			ProductModule: profile.Permissions.ProductModule,
-		},
+		Id:          profile.Id,
+		Name:        profile.Name,
+		TwoFactor:   &profile.TwoFactor,
+		Permissions: generatePermissionsOutputDTO(profile),
+		Headline:    profile.Headline,
 	}
 }
 
+// GenerateProfileOutputDTO Implementation of 'GenerateProfileOutputDTO'.
+func (s *profileService) GenerateProfileOutputDTO(profile *domain.Profile) *dto.ProfileOutputDTO {
+	return s.generateProfileOutputDTO(profile)
+}
+
 // GetProfileByID Implementation of 'GetProfileByID'.
 func (s *profileService) GetProfileByID(ctx context.Context, profileID uint) (*dto.ProfileOutputDTO, error) {
 	profile, err := s.profileRepository.GetProfileByID(ctx, profileID)
@@ -42,9 +64,13 @@ func (s *profileService) GetProfileByID(ctx context.Context, profileID uint) (*d
 
 // GetProfiles Implementation of 'GetProfiles'.
 func (s *profileService) GetProfiles(ctx context.Context, filter *filter.Filter) (*dto.ListItemsOutputDTO, error) {
-	count, err := s.profileRepository.CountProfiles(ctx, filter)
-	if err != nil {
-		return nil, err
+	var count *int64
+	if filter.WithCount() {
+		total, err := s.profileRepository.CountProfiles(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
 	}
 
 	profiles, err := s.profileRepository.GetProfiles(ctx, filter)
@@ -57,13 +83,23 @@ func (s *profileService) GetProfiles(ctx context.Context, filter *filter.Filter)
 		*outputProfiles = append(*outputProfiles, *s.generateProfileOutputDTO(&profile))
 	}
 	return &dto.ListItemsOutputDTO{
-		Count: count,
-		Items: outputProfiles,
+		Count:      count,
+		Items:      outputProfiles,
+		NextCursor: filter.NextCursor,
+		PrevCursor: filter.PrevCursor,
 	}, nil
 }
 
-// CreateProfile Implementation of 'CreateProfile'.
-func (s *profileService) CreateProfile(ctx context.Context, data *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error) {
+// CreateProfile Implementation of 'CreateProfile', the grantor must hold every permission of the profile.
+func (s *profileService) CreateProfile(ctx context.Context, grantor *domain.Profile, data *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error) {
+	candidate := &domain.Profile{}
+	if err := candidate.Bind(data); err != nil {
+		return nil, err
+	}
+	if !grantor.CanGrant(candidate) {
+		return nil, domain.ErrGrantForbidden
+	}
+
 	profile, err := s.profileRepository.CreateProfile(ctx, data)
 	if err != nil {
 		return nil, err
@@ -72,8 +108,18 @@ func (s *profileService) CreateProfile(ctx context.Context, data *dto.ProfileInp
 	return s.generateProfileOutputDTO(profile), nil
 }
 
-// UpdateProfile Implementation of 'UpdateProfile'.
-func (s *profileService) UpdateProfile(ctx context.Context, profile *domain.Profile, data *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error) {
+// UpdateProfile Implementation of 'UpdateProfile', the grantor must hold every permission of the profile,
+// before and after the update.
+func (s *profileService) UpdateProfile(ctx context.Context, grantor, profile *domain.Profile, data *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error) {
+	candidate := *profile
+	candidate.Permissions = slices.Clone(profile.Permissions)
+	if err := candidate.Bind(data); err != nil {
+		return nil, err
+	}
+	if !grantor.CanGrant(profile) || !grantor.CanGrant(&candidate) {
+		return nil, domain.ErrGrantForbidden
+	}
+
 	if err := s.profileRepository.UpdateProfile(ctx, profile, data); err != nil {
 		return nil, err
 	}
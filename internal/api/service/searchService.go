@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+)
+
+func NewSearchService(us domain.UserService, ps domain.ProfileService, prs domain.ProductService) domain.SearchService {
+	return &searchService{
+		userService:    us,
+		profileService: ps,
+		productService: prs,
+	}
+}
+
+type searchService struct {
+	userService    domain.UserService
+	profileService domain.ProfileService
+	productService domain.ProductService
+}
+
+// Search Implementation of 'Search'.
+func (s *searchService) Search(ctx context.Context, search *filter.Filter, modules []string) (*dto.SearchOutputDTO, error) {
+	// Every module is searched with its own copy, as the lists keep their state in the filter.
+	module := func() *filter.Filter {
+		current := *search
+		current.Cursor, current.Conditions = nil, nil
+		return &current
+	}
+
+	var err error
+	output := &dto.SearchOutputDTO{}
+	if slices.Contains(modules, domain.ModuleUser) {
+		if output.Users, err = s.userService.GetUsers(ctx, &filter.UserFilter{Filter: *module()}); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(modules, domain.ModuleProfile) {
+		if output.Profiles, err = s.profileService.GetProfiles(ctx, module()); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(modules, domain.ModuleProduct) {
+		if output.Products, err = s.productService.GetProducts(ctx, module()); err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
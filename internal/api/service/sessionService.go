@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+)
+
+func NewSessionService(r domain.SessionRepository) domain.SessionService {
+	return &sessionService{
+		sessionRepository: r,
+	}
+}
+
+type sessionService struct {
+	sessionRepository domain.SessionRepository
+}
+
+func (s *sessionService) generateSessionOutputDTO(session *domain.Session, current *domain.Session) *dto.SessionOutputDTO {
+	return &dto.SessionOutputDTO{
+		Id:             session.Id,
+		IP:             session.IP,
+		Agent:          session.Agent,
+		CreatedAt:      session.CreatedAt,
+		LastSeen:       session.LastSeen,
+		Current:        current != nil && current.Id == session.Id,
+		ImpersonatorID: session.ImpersonatorID,
+	}
+}
+
+// GetUserSessions Implementation of 'GetUserSessions'.
+func (s *sessionService) GetUserSessions(ctx context.Context, user *domain.User, current *domain.Session) (*[]dto.SessionOutputDTO, error) {
+	sessions, err := s.sessionRepository.GetUserSessions(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	outputSessions := &[]dto.SessionOutputDTO{}
+	for _, session := range *sessions {
+		*outputSessions = append(*outputSessions, *s.generateSessionOutputDTO(&session, current))
+	}
+
+	return outputSessions, nil
+}
+
+// RevokeSession Implementation of 'RevokeSession'.
+func (s *sessionService) RevokeSession(ctx context.Context, session *domain.Session) error {
+	return s.sessionRepository.RevokeSession(ctx, session)
+}
+
+// RevokeUserSession Implementation of 'RevokeUserSession'.
+func (s *sessionService) RevokeUserSession(ctx context.Context, user *domain.User, sessionID uint) error {
+	return s.sessionRepository.RevokeUserSession(ctx, user.Id, sessionID)
+}
+
+// RevokeUserSessions Implementation of 'RevokeUserSessions'.
+func (s *sessionService) RevokeUserSessions(ctx context.Context, user *domain.User) error {
+	return s.sessionRepository.RevokeUserSessions(ctx, user.Id)
+}
@@ -2,33 +2,237 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"time"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/mailer"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/password"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultInviteExpire    = 48 * time.Hour
+	defaultResetExpire     = time.Hour
+	defaultPasswordHistory = 5
+	defaultVerifyExpire    = 48 * time.Hour
+)
+
+const (
+	defaultRegisterProfile      = "VOLUNTEER"
+	defaultRegisterIPAttempts   = 20
+	defaultRegisterMailAttempts = 3
+	defaultRegisterWindow       = time.Hour
+	defaultRegisterMaxLock      = 24 * time.Hour
 )
 
-func NewUserService(r domain.UserRepository) domain.UserService {
+// passwordMails Mails of the one time tokens, 'url' is the setting holding the address of the link.
+var passwordMails = map[string]struct {
+	subject string
+	body    string
+	url     string
+}{
+	domain.TokenInvite: {
+		subject: "Welcome to MSAADA",
+		body:    "Hello %s,\n\nAn account was created for you. Use the link below to set your password, it expires in %v:\n\n%s\n",
+		url:     "MAIL_PASSWORD_URL",
+	},
+	domain.TokenReset: {
+		subject: "MSAADA password reset",
+		body:    "Hello %s,\n\nYour password was reset. Use the link below to set a new one, it expires in %v:\n\n%s\n",
+		url:     "MAIL_PASSWORD_URL",
+	},
+	domain.TokenVerify: {
+		subject: "Confirm your MSAADA registration",
+		body:    "Hello %s,\n\nThanks for volunteering. Use the link below to confirm your mail, it expires in %v:\n\n%s\n",
+		url:     "REGISTER_VERIFY_URL",
+	},
+}
+
+func NewUserService(r domain.UserRepository, pr domain.ProfileRepository, sr domain.SessionRepository, tr domain.PasswordTokenRepository, ar domain.AttemptRepository, sender mailer.Sender) domain.UserService {
+	profile := os.Getenv("REGISTER_PROFILE")
+	if profile == "" {
+		profile = defaultRegisterProfile
+	}
+
 	return &userService{
-		userRepository: r,
+		userRepository:          r,
+		profileRepository:       pr,
+		sessionRepository:       sr,
+		passwordTokenRepository: tr,
+		attemptRepository:       ar,
+		sender:                  sender,
+		passwords:               newPasswordValidator(r),
+		registerProfile:         profile,
+		registerApproval:        envBool("REGISTER_APPROVAL", false),
+		registerIPPolicy:        registerPolicy("REGISTER_IP_MAX_ATTEMPTS", defaultRegisterIPAttempts),
+		registerMailPolicy:      registerPolicy("REGISTER_MAIL_MAX_ATTEMPTS", defaultRegisterMailAttempts),
 	}
 }
 
 type userService struct {
+	userRepository          domain.UserRepository
+	profileRepository       domain.ProfileRepository
+	sessionRepository       domain.SessionRepository
+	passwordTokenRepository domain.PasswordTokenRepository
+	attemptRepository       domain.AttemptRepository
+	sender                  mailer.Sender
+	passwords               *passwordValidator
+	registerProfile         string
+	registerApproval        bool
+	registerIPPolicy        *domain.AttemptPolicy
+	registerMailPolicy      *domain.AttemptPolicy
+}
+
+// registerPolicy Reads the sign-up quota, the limit from 'limitEnv' and the window, in minutes, from
+// 'REGISTER_WINDOW'. Once reached the key is locked for the window, doubling up to a day.
+func registerPolicy(limitEnv string, limit int) *domain.AttemptPolicy {
+	window := defaultRegisterWindow
+	if configured, err := helpers.DurationFromString(os.Getenv("REGISTER_WINDOW"), time.Minute); err == nil {
+		window = configured
+	}
+
+	return &domain.AttemptPolicy{
+		Limit:   envInt(limitEnv, limit),
+		Window:  window,
+		Lock:    window,
+		MaxLock: defaultRegisterMaxLock,
+	}
+}
+
+// newPasswordPolicy Reads the 'PASSWORD_*' settings over the default password policy.
+func newPasswordPolicy() *password.Policy {
+	policy := password.DefaultPolicy()
+	policy.MinLength = envInt("PASSWORD_MIN_LENGTH", policy.MinLength)
+	policy.MaxLength = envInt("PASSWORD_MAX_LENGTH", policy.MaxLength)
+	policy.RequireUpper = envBool("PASSWORD_REQUIRE_UPPER", policy.RequireUpper)
+	policy.RequireLower = envBool("PASSWORD_REQUIRE_LOWER", policy.RequireLower)
+	policy.RequireDigit = envBool("PASSWORD_REQUIRE_DIGIT", policy.RequireDigit)
+	policy.RequireSymbol = envBool("PASSWORD_REQUIRE_SYMBOL", policy.RequireSymbol)
+	policy.BanPersonal = envBool("PASSWORD_BAN_PERSONAL", policy.BanPersonal)
+	policy.BanCommon = envBool("PASSWORD_BAN_COMMON", policy.BanCommon)
+	return policy
+}
+
+// passwordValidator Checks the new passwords of the users, shared by the services setting them.
+type passwordValidator struct {
 	userRepository domain.UserRepository
+	policy         *password.Policy
+	history        int
+}
+
+func newPasswordValidator(r domain.UserRepository) *passwordValidator {
+	return &passwordValidator{
+		userRepository: r,
+		policy:         newPasswordPolicy(),
+		history:        envInt("PASSWORD_HISTORY", defaultPasswordHistory),
+	}
+}
+
+// validate Applies the password policy and refuses the last 'PASSWORD_HISTORY' passwords of the user.
+func (s *passwordValidator) validate(ctx context.Context, user *domain.User, pass string) error {
+	if err := s.policy.Validate(pass, user.Name, user.Email); err != nil {
+		return err
+	}
+
+	if s.history < 1 {
+		return nil
+	}
+
+	if user.Password != nil && user.ValidatePassword(pass) {
+		return domain.ErrPasswordReused
+	}
+
+	history, err := s.userRepository.GetPasswordHistory(ctx, user.Id, s.history)
+	if err != nil {
+		return err
+	}
+
+	for _, old := range history {
+		if old.Matches(pass) {
+			return domain.ErrPasswordReused
+		}
+	}
+
+	return nil
+}
+
+func (s *userService) passwordTokenExpiration(kind string) time.Duration {
+	env, life := "RESET_TOKEN_EXPIRE", defaultResetExpire
+	switch kind {
+	case domain.TokenInvite:
+		env, life = "INVITE_TOKEN_EXPIRE", defaultInviteExpire
+	case domain.TokenVerify:
+		env, life = "VERIFY_TOKEN_EXPIRE", defaultVerifyExpire
+	}
+
+	if configured, err := helpers.DurationFromString(os.Getenv(env), time.Minute); err == nil {
+		return configured
+	}
+
+	return life
 }
 
-func (s *userService) generateUserOutputDTO(user *domain.User) *dto.UserOutputDTO {
-	return &dto.UserOutputDTO{
-		Id:     user.Id,
-		Name:   user.Name,
-		Email:  user.Email,
-		Status: user.Status,
+// sendPasswordToken Issues a one time token of the kind and mails the user the link to use it.
+func (s *userService) sendPasswordToken(ctx context.Context, user *domain.User, kind string) error {
+	life := s.passwordTokenExpiration(kind)
+	token, raw, err := domain.NewPasswordToken(user, kind, life)
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwordTokenRepository.CreatePasswordToken(ctx, token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?email=%s&token=%s", os.Getenv(passwordMails[kind].url), url.QueryEscape(user.Email), raw)
+	return s.sender.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: passwordMails[kind].subject,
+		Body:    fmt.Sprintf(passwordMails[kind].body, user.Name, life, link),
+	})
+}
+
+// generateUserOutputDTO Embeds the relations included by the fieldset, the default ones without a fieldset.
+func (s *userService) generateUserOutputDTO(user *domain.User, fieldset *filter.Fieldset) *dto.UserOutputDTO {
+	if fieldset == nil {
+		fieldset = filter.DefaultFieldset(domain.UserRelations)
+	}
+
+	output := &dto.UserOutputDTO{
+		Id:       user.Id,
+		Name:     user.Name,
+		Email:    user.Email,
+		Status:   user.Status,
+		Verified: !user.VerifyPending,
 		Profile: dto.ProfileOutputDTO{
-			Id:   user.ProfileID,
-			Name: user.Profile.Name,
+			Id: user.ProfileID,
 		},
+		Headline: user.Headline,
 	}
+
+	if user.Profile != nil && fieldset.Includes("profile") {
+		output.Profile.Name = user.Profile.Name
+		output.Profile.TwoFactor = &user.Profile.TwoFactor
+	}
+	if user.Profile != nil && fieldset.Includes("profile.permissions") {
+		output.Profile.Permissions = generatePermissionsOutputDTO(user.Profile)
+	}
+
+	return output
+}
+
+// GenerateUserOutputDTO Implementation of 'GenerateUserOutputDTO'.
+func (s *userService) GenerateUserOutputDTO(user *domain.User, fieldset *filter.Fieldset) *dto.UserOutputDTO {
+	return s.generateUserOutputDTO(user, fieldset)
 }
 
 // GetUserByID Implementation of 'GetUserByID'.
@@ -38,14 +242,18 @@ func (s *userService) GetUserByID(ctx context.Context, userID uint) (*dto.UserOu
 		return nil, err
 	}
 
-	return s.generateUserOutputDTO(user), nil
+	return s.generateUserOutputDTO(user, nil), nil
 }
 
 // GetUsers Implementation of 'GetUsers'.
 func (s *userService) GetUsers(ctx context.Context, filter *filter.UserFilter) (*dto.ListItemsOutputDTO, error) {
-	count, err := s.userRepository.CountUsers(ctx, filter)
-	if err != nil {
-		return nil, err
+	var count *int64
+	if filter.WithCount() {
+		total, err := s.userRepository.CountUsers(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
 	}
 
 	users, err := s.userRepository.GetUsers(ctx, filter)
@@ -55,12 +263,14 @@ func (s *userService) GetUsers(ctx context.Context, filter *filter.UserFilter) (
 
 	outputUsers := &[]dto.UserOutputDTO{}
 	for _, user := range *users {
-		*outputUsers = append(*outputUsers, *s.generateUserOutputDTO(&user))
+		*outputUsers = append(*outputUsers, *s.generateUserOutputDTO(&user, filter.Fieldset))
 	}
 
 	return &dto.ListItemsOutputDTO{
-		Count: count,
-		Items: outputUsers,
+		Count:      count,
+		Items:      outputUsers,
+		NextCursor: filter.NextCursor,
+		PrevCursor: filter.PrevCursor,
 	}, nil
 }
 
@@ -74,23 +284,66 @@ func (s *userService) GetUserByToken(ctx context.Context, token string) (*domain
 	return s.userRepository.GetUserByToken(ctx, token)
 }
 
-// CreateUser Implementation of 'CreateUser'.
-func (s *userService) CreateUser(ctx context.Context, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
+// checkProfileGrant Denies assigning a profile with permissions the grantor does not hold.
+func (s *userService) checkProfileGrant(ctx context.Context, grantor *domain.Profile, profileID uint) error {
+	profile, err := s.profileRepository.GetProfileByID(ctx, profileID)
+	if err != nil {
+		// An unknown profile is reported by the foreign key.
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !grantor.CanGrant(profile) {
+		return domain.ErrGrantForbidden
+	}
+
+	return nil
+}
+
+// CreateUser Implementation of 'CreateUser', the grantor must hold every permission of the profile.
+func (s *userService) CreateUser(ctx context.Context, grantor *domain.Profile, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
+	if data.ProfileID != nil {
+		if err := s.checkProfileGrant(ctx, grantor, *data.ProfileID); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := s.userRepository.CreateUser(ctx, data)
 	if err != nil {
 		return nil, err
 	}
 
+	// The invitation can be sent again through 'ResetUserPassword'.
+	if err := s.sendPasswordToken(ctx, user, domain.TokenInvite); err != nil {
+		log.Println(err.Error())
+	}
+
 	user, err = s.userRepository.GetUserByID(ctx, user.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.generateUserOutputDTO(user), nil
+	return s.generateUserOutputDTO(user, nil), nil
 }
 
-// UpdateUser Implementation of 'UpdateUser'.
-func (s *userService) UpdateUser(ctx context.Context, user *domain.User, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
+// UpdateUser Implementation of 'UpdateUser', moving the user to another profile requires the grantor
+// to hold every permission of both profiles.
+func (s *userService) UpdateUser(ctx context.Context, grantor *domain.Profile, user *domain.User, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
+	if data.ProfileID != nil && *data.ProfileID != user.ProfileID {
+		if !grantor.CanGrant(user.Profile) {
+			return nil, domain.ErrGrantForbidden
+		}
+		if err := s.checkProfileGrant(ctx, grantor, *data.ProfileID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.updateUser(ctx, user, data)
+}
+
+func (s *userService) updateUser(ctx context.Context, user *domain.User, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
 	if err := s.userRepository.UpdateUser(ctx, user, data); err != nil {
 		return nil, err
 	}
@@ -100,7 +353,14 @@ func (s *userService) UpdateUser(ctx context.Context, user *domain.User, data *d
 		return nil, err
 	}
 
-	return s.generateUserOutputDTO(user), nil
+	// A disabled user loses its sessions, and the impersonation sessions it opened as an admin.
+	if !user.Status {
+		if err := s.sessionRepository.RevokeUserSessions(ctx, user.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.generateUserOutputDTO(user, nil), nil
 }
 
 // DeleteUser Implementation of 'DeleteUser'.
@@ -108,12 +368,117 @@ func (s *userService) DeleteUser(ctx context.Context, user *domain.User) error {
 	return s.userRepository.DeleteUser(ctx, user)
 }
 
-// ResetUserPassword Implementation of 'ResetUserPassword'.
+// ResetUserPassword Implementation of 'ResetUserPassword', users that never set a password receive the invitation again.
 func (s *userService) ResetUserPassword(ctx context.Context, user *domain.User) error {
-	return s.userRepository.ResetUserPassword(ctx, user)
+	if user.New {
+		return s.sendPasswordToken(ctx, user, domain.TokenInvite)
+	}
+
+	if err := s.userRepository.ResetUserPassword(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepository.RevokeUserSessions(ctx, user.Id); err != nil {
+		return err
+	}
+
+	return s.sendPasswordToken(ctx, user, domain.TokenReset)
 }
 
-// SetUserPassword Implementation of 'SetUserPassword'.
+// SetUserPassword Implementation of 'SetUserPassword', the token is consumed along with the new password.
 func (s *userService) SetUserPassword(ctx context.Context, user *domain.User, pass *dto.PasswordInputDTO) error {
-	return s.userRepository.SetUserPassword(ctx, user, pass)
+	if pass.Token == nil {
+		return domain.ErrInvalidPasswordToken
+	}
+
+	// The token is checked before the policy, so the password history is never compared for callers
+	// without a valid token.
+	if err := s.passwordTokenRepository.CheckPasswordToken(ctx, user.Id, domain.HashToken(*pass.Token), domain.TokenInvite, domain.TokenReset); err != nil {
+		return err
+	}
+
+	if err := s.passwords.validate(ctx, user, *pass.Password); err != nil {
+		return err
+	}
+
+	if err := s.userRepository.SetUserPassword(ctx, user, pass); err != nil {
+		return err
+	}
+
+	return s.sessionRepository.RevokeUserSessions(ctx, user.Id)
+}
+
+// UnlockUser Implementation of 'UnlockUser'.
+func (s *userService) UnlockUser(ctx context.Context, user *domain.User) error {
+	return s.attemptRepository.ResetAttempts(ctx, domain.LoginMailKey(user.Email))
+}
+
+// RegisterUser Implementation of 'RegisterUser', a mail already registered gets the same answer so
+// the sign-up can not be used to find the accounts, a pending one receives the verification again.
+func (s *userService) RegisterUser(ctx context.Context, data *dto.RegisterInputDTO, ip string) error {
+	ipKey, mailKey := domain.RegisterIPKey(ip), domain.RegisterMailKey(*data.Email)
+	if err := checkLocks(ctx, s.attemptRepository, ipKey, mailKey); err != nil {
+		return err
+	}
+
+	if err := s.passwords.validate(ctx, &domain.User{Name: *data.Name, Email: *data.Email}, *data.Password); err != nil {
+		return err
+	}
+
+	if _, err := s.attemptRepository.RegisterAttempt(ctx, ipKey, s.registerIPPolicy); err != nil {
+		return err
+	}
+
+	if _, err := s.attemptRepository.RegisterAttempt(ctx, mailKey, s.registerMailPolicy); err != nil {
+		return err
+	}
+
+	user, err := s.userRepository.GetUserByMail(ctx, *data.Email)
+	if err == nil {
+		if user.VerifyPending {
+			return s.sendPasswordToken(ctx, user, domain.TokenVerify)
+		}
+
+		return nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	profile, err := s.profileRepository.GetProfileByName(ctx, s.registerProfile)
+	if err != nil {
+		return err
+	}
+
+	user, err = s.userRepository.RegisterUser(ctx, data, profile.Id)
+	if err != nil {
+		return err
+	}
+
+	return s.sendPasswordToken(ctx, user, domain.TokenVerify)
+}
+
+// VerifyUser Implementation of 'VerifyUser', the user is enabled unless 'REGISTER_APPROVAL' requires a coordinator.
+func (s *userService) VerifyUser(ctx context.Context, data *dto.VerifyInputDTO) error {
+	user, err := s.userRepository.GetUserByMail(ctx, data.Email)
+	if err != nil || !user.VerifyPending {
+		return domain.ErrInvalidPasswordToken
+	}
+
+	if err := s.passwordTokenRepository.UsePasswordToken(ctx, user.Id, domain.HashToken(data.Token), domain.TokenVerify); err != nil {
+		return err
+	}
+
+	return s.userRepository.VerifyUser(ctx, user, !s.registerApproval)
+}
+
+// ApproveUser Implementation of 'ApproveUser'.
+func (s *userService) ApproveUser(ctx context.Context, user *domain.User) (*dto.UserOutputDTO, error) {
+	if user.VerifyPending {
+		return nil, domain.ErrUserNotVerified
+	}
+
+	status := true
+	return s.updateUser(ctx, user, &dto.UserInputDTO{Status: &status})
 }
@@ -6,34 +6,177 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/hasher"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
 )
 
+// legacyPermissions Module flags table replaced by the per module action grants.
+type legacyPermissions struct {
+	ProfileID uint `gorm:"column:profile_id"`
+	User      bool `gorm:"column:user"`
+	Profile   bool `gorm:"column:profile"`
+	Product   bool `gorm:"column:product"`
+}
+
+func (s *legacyPermissions) TableName() string {
+	return domain.PermissionTableName
+}
+
+// migrateLegacyPermissions Turns every enabled module flag into a full grant of that module.
+func migrateLegacyPermissions(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&legacyPermissions{}, "user") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		legacy := []legacyPermissions{}
+		if err := tx.Find(&legacy).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Migrator().DropTable(&legacyPermissions{}); err != nil {
+			return err
+		}
+
+		if err := tx.AutoMigrate(&domain.Permission{}, &domain.Profile{}); err != nil {
+			return err
+		}
+
+		permissions := []domain.Permission{}
+		for _, old := range legacy {
+			for module, allowed := range map[string]bool{domain.ModuleUser: old.User, domain.ModuleProfile: old.Profile, domain.ModuleProduct: old.Product} {
+				permissions = append(permissions, domain.Permission{
+					ProfileID: old.ProfileID,
+					Module:    module,
+					Read:      allowed,
+					Create:    allowed,
+					Update:    allowed,
+					Delete:    allowed,
+				})
+			}
+		}
+
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		return tx.Create(&permissions).Error
+	})
+}
+
+// protectAuditLogs Makes the audit log append only, refusing any update, delete or truncate of its rows.
+func protectAuditLogs(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range []string{
+			`CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS trigger AS $$
+			BEGIN
+				RAISE EXCEPTION 'audit logs are append only';
+			END;
+			$$ LANGUAGE plpgsql`,
+			`DROP TRIGGER IF EXISTS audit_logs_immutable ON ` + domain.AuditLogTableName,
+			`CREATE TRIGGER audit_logs_immutable BEFORE UPDATE OR DELETE ON ` + domain.AuditLogTableName + ` FOR EACH ROW EXECUTE FUNCTION audit_logs_immutable()`,
+			`DROP TRIGGER IF EXISTS audit_logs_truncate ON ` + domain.AuditLogTableName,
+			`CREATE TRIGGER audit_logs_truncate BEFORE TRUNCATE ON ` + domain.AuditLogTableName + ` FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_immutable()`,
+		} {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// createSearchVectors Adds the generated 'tsvector' column of the full text search, with its GIN index, to
+// the searched tables. The text search configuration is 'simple' without the accents, as the names and
+// mails are not stemmed in any language.
+func createSearchVectors(db *gorm.DB) error {
+	documents := map[string]string{
+		domain.UserTableName:    filter.SearchDocument("name", "mail"),
+		domain.ProfileTableName: filter.SearchDocument("name"),
+		domain.ProductTableName: filter.SearchDocument("name"),
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = '` + filter.TextSearchConfig + `') THEN
+					CREATE TEXT SEARCH CONFIGURATION ` + filter.TextSearchConfig + ` (COPY = simple);
+					ALTER TEXT SEARCH CONFIGURATION ` + filter.TextSearchConfig + ` ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+				END IF;
+			END
+			$$`,
+		}
+		for table, document := range documents {
+			statements = append(statements,
+				`ALTER TABLE `+table+` ADD COLUMN IF NOT EXISTS `+filter.SearchVectorColumn+` tsvector GENERATED ALWAYS AS (to_tsvector('`+filter.TextSearchConfig+`', `+document+`)) STORED`,
+				`CREATE INDEX IF NOT EXISTS idx_`+table+`_`+filter.SearchVectorColumn+` ON `+table+` USING GIN (`+filter.SearchVectorColumn+`)`,
+			)
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func autoMigrate(db *gorm.DB) {
-	helpers.PanicIfErr(db.AutoMigrate(&domain.Permissions{}))
+	helpers.PanicIfErr(migrateLegacyPermissions(db))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.Permission{}))
 	helpers.PanicIfErr(db.AutoMigrate(&domain.Profile{}))
 	helpers.PanicIfErr(db.AutoMigrate(&domain.User{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.Session{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.PasswordToken{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.PasswordHistory{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.RecoveryCode{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.UsedTwoFactor{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.Attempt{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.RateLimit{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.SigningKey{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.OidcState{}))
 	helpers.PanicIfErr(db.AutoMigrate(&domain.Product{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.ApiKey{}))
+	helpers.PanicIfErr(db.AutoMigrate(&domain.AuditLog{}))
+	helpers.PanicIfErr(protectAuditLogs(db))
+	helpers.PanicIfErr(createSearchVectors(db))
 }
 
 func createDefaults(db *gorm.DB) {
 	profile := &domain.Profile{
 		Name: "ROOT",
-		Permissions: domain.Permissions{
-			UserModule:    true,
-			ProfileModule: true,
-			ProductModule: true,
-		},
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	helpers.PanicIfErr(db.WithContext(ctx).FirstOrCreate(profile, "name = ?", profile.Name).Error)
 
+	// ROOT is granted every module, including the ones added after its creation.
+	for _, module := range domain.Modules {
+		permission := &domain.Permission{
+			ProfileID: profile.Id,
+			Module:    module,
+			Read:      true,
+			Create:    true,
+			Update:    true,
+			Delete:    true,
+		}
+
+		helpers.PanicIfErr(db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "profile_id"}, {Name: "module"}},
+			DoUpdates: clause.AssignmentColumns([]string{"read", "create", "update", "delete"}),
+		}).Create(permission).Error)
+	}
+
 	user := &domain.User{
 		Name:      os.Getenv("ADM_NAME"),
 		Email:     os.Getenv("ADM_MAIL"),
@@ -47,10 +190,16 @@ func createDefaults(db *gorm.DB) {
 	token := uuid.New().String()
 	*user.Token = token
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(os.Getenv("ADM_PASS")), bcrypt.DefaultCost)
+	hash, err := hasher.Hash(os.Getenv("ADM_PASS"))
 	helpers.PanicIfErr(err)
-	user.Password = new(string)
-	*user.Password = string(hash)
+	user.Password = &hash
 
 	helpers.PanicIfErr(db.WithContext(ctx).FirstOrCreate(user, "mail = ?", user.Email).Error)
+
+	// The self registered volunteers join this profile, created without grants.
+	volunteer := &domain.Profile{Name: os.Getenv("REGISTER_PROFILE")}
+	if volunteer.Name == "" {
+		volunteer.Name = "VOLUNTEER"
+	}
+	helpers.PanicIfErr(db.WithContext(ctx).FirstOrCreate(volunteer, "name = ?", volunteer.Name).Error)
 }
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
@@ -15,20 +18,42 @@ import (
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/i18n"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/repository"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
 	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/ipbinding"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/mailer"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/oidc"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/ratelimit"
 
 	"gorm.io/gorm"
 )
 
 var (
-	profileRepository domain.ProfileRepository
-	userRepository    domain.UserRepository
-	productRepository domain.ProductRepository
+	profileRepository       domain.ProfileRepository
+	userRepository          domain.UserRepository
+	productRepository       domain.ProductRepository
+	sessionRepository       domain.SessionRepository
+	passwordTokenRepository domain.PasswordTokenRepository
+	twoFactorRepository     domain.TwoFactorRepository
+	attemptRepository       domain.AttemptRepository
+	signingKeyRepository    domain.SigningKeyRepository
+	apiKeyRepository        domain.ApiKeyRepository
+	oidcStateRepository     domain.OidcStateRepository
+	auditRepository         domain.AuditRepository
+	rateLimitRepository     domain.RateLimitRepository
+
+	accessKeys  *keyring.Keyring
+	refreshKeys *keyring.Keyring
 
 	profileService domain.ProfileService
 	userService    domain.UserService
 	authService    domain.AuthService
 	productService domain.ProductService
+	sessionService domain.SessionService
+	apiKeyService  domain.ApiKeyService
+	auditService   domain.AuditService
+	searchService  domain.SearchService
 )
 
 func initRepositories(postgresdb *gorm.DB) {
@@ -36,29 +61,161 @@ func initRepositories(postgresdb *gorm.DB) {
 	profileRepository = repository.NewProfileRepository(postgresdb)
 	userRepository = repository.NewUserRepository(postgresdb)
 	productRepository = repository.NewProductRepository(postgresdb)
+	sessionRepository = repository.NewSessionRepository(postgresdb)
+	passwordTokenRepository = repository.NewPasswordTokenRepository(postgresdb)
+	twoFactorRepository = repository.NewTwoFactorRepository(postgresdb)
+	attemptRepository = repository.NewAttemptRepository(postgresdb)
+	signingKeyRepository = repository.NewSigningKeyRepository(postgresdb)
+	apiKeyRepository = repository.NewApiKeyRepository(postgresdb)
+	oidcStateRepository = repository.NewOidcStateRepository(postgresdb)
+	auditRepository = repository.NewAuditRepository(postgresdb)
+	rateLimitRepository = repository.NewRateLimitRepository(postgresdb)
+}
+
+// newKeyring Loads the signing keys of the token type, the key of the environment variable is
+// imported and only becomes the current one when there is no other.
+func newKeyring(use, env string) *keyring.Keyring {
+	ctx := context.Background()
+	if encoded := os.Getenv(env); encoded != "" {
+		key, err := keyring.ParseBase64Key(encoded)
+		helpers.PanicIfErr(err)
+		helpers.PanicIfErr(signingKeyRepository.ImportSigningKey(ctx, domain.NewSigningKey(use, key)))
+	}
+
+	keys, err := keyring.New(ctx, domain.SigningKeySource(signingKeyRepository, use))
+	helpers.PanicIfErr(err)
+
+	go keys.Watch(ctx, domain.SigningKeyRefresh)
+	return keys
+}
+
+func initKeyrings() {
+	// Create the keyrings of the tokens.
+	accessKeys = newKeyring(domain.KeyAccess, "ACCESS_TOKEN_PRIVAT")
+	refreshKeys = newKeyring(domain.KeyRefresh, "RFRESH_TOKEN_PRIVAT")
+}
+
+// newMailSender Selects the mail sender by 'MAIL_DRIVER', messages are only logged unless it is 'smtp'.
+func newMailSender() mailer.Sender {
+	if strings.ToLower(os.Getenv("MAIL_DRIVER")) == "smtp" {
+		return mailer.NewSMTPSender(os.Getenv("MAIL_HOST"), os.Getenv("MAIL_PORT"), os.Getenv("MAIL_USER"), os.Getenv("MAIL_PASS"), os.Getenv("MAIL_FROM"))
+	}
+
+	return mailer.NewLogSender(os.Getenv("MAIL_LOG_FILE"))
+}
+
+// newOidcProvider Configures the single sign-on of 'OIDC_ISSUER', it is disabled when the issuer is not set.
+func newOidcProvider() *oidc.Provider {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil
+	}
+
+	return oidc.New(oidc.Config{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	})
+}
+
+// newAuthCookies Enables the cookie mode when 'AUTH_COOKIE' is true, the cookies are set for
+// 'AUTH_COOKIE_DOMAIN' with the SameSite mode of 'AUTH_COOKIE_SAMESITE' (Strict by default) and are
+// only sent over HTTPS unless 'AUTH_COOKIE_INSECURE' is true.
+func newAuthCookies() *middleware.CookieConfig {
+	if strings.ToLower(os.Getenv("AUTH_COOKIE")) != "true" {
+		return nil
+	}
+
+	sameSite := fiber.CookieSameSiteStrictMode
+	switch strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")) {
+	case "lax":
+		sameSite = fiber.CookieSameSiteLaxMode
+	case "none":
+		sameSite = fiber.CookieSameSiteNoneMode
+	}
+
+	return &middleware.CookieConfig{
+		Domain:   os.Getenv("AUTH_COOKIE_DOMAIN"),
+		Secure:   strings.ToLower(os.Getenv("AUTH_COOKIE_INSECURE")) != "true",
+		SameSite: sameSite,
+	}
+}
+
+// newRateLimitStore Selects the store of the rate limits by 'RATE_LIMIT_STORE', the counters are kept
+// in memory unless it is 'postgres', which is needed to share them between instances.
+func newRateLimitStore() ratelimit.Store {
+	if strings.ToLower(os.Getenv("RATE_LIMIT_STORE")) == "postgres" {
+		return rateLimitRepository
+	}
+
+	return ratelimit.NewMemoryStore()
+}
+
+// newRateLimit Reads the policy of the name, its limit from 'RATE_LIMIT_<NAME>_MAX' and its window,
+// in minutes, from 'RATE_LIMIT_<NAME>_WINDOW'.
+func newRateLimit(store ratelimit.Store, name string, limit int, window time.Duration) *ratelimit.Limiter {
+	env := "RATE_LIMIT_" + strings.ToUpper(name)
+	if configured, err := strconv.Atoi(os.Getenv(env + "_MAX")); err == nil && configured > 0 {
+		limit = configured
+	}
+
+	if configured, err := helpers.DurationFromString(os.Getenv(env+"_WINDOW"), time.Minute); err == nil {
+		window = configured
+	}
+
+	return ratelimit.New(ratelimit.Policy{Name: name, Limit: limit, Window: window}, store)
 }
 
 func initServices() {
 	// Create services.
 	profileService = service.NewProfileService(profileRepository)
-	userService = service.NewUserService(userRepository)
-	authService = service.NewAuthService(userRepository)
+	userService = service.NewUserService(userRepository, profileRepository, sessionRepository, passwordTokenRepository, attemptRepository, newMailSender())
+	authService = service.NewAuthService(userRepository, profileRepository, sessionRepository, twoFactorRepository, attemptRepository, oidcStateRepository, auditRepository, accessKeys, refreshKeys, newOidcProvider())
 	productService = service.NewProductService(productRepository)
+	sessionService = service.NewSessionService(sessionRepository)
+	apiKeyService = service.NewApiKeyService(apiKeyRepository, profileRepository)
+	auditService = service.NewAuditService(auditRepository)
+	searchService = service.NewSearchService(userService, profileService, productService)
 }
 
 func initHandelrs(app *fiber.App, db *gorm.DB) {
 	reqMid := middleware.NewRequesttMiddleware(db)
 
+	// Tokens are bound to the client address according to 'AUTH_IP_BINDING' (off, subnet or strict).
+	binding, err := ipbinding.ParseMode(os.Getenv("AUTH_IP_BINDING"))
+	helpers.PanicIfErr(err)
+
 	// Initialize access middleares
-	middleware.MidAccess = middleware.Auth(os.Getenv("ACCESS_TOKEN_PUBLIC"), userRepository)
-	middleware.MidRefresh = middleware.Auth(os.Getenv("RFRESH_TOKEN_PUBLIC"), userRepository)
+	middleware.MidAccess = middleware.Auth(accessKeys, sessionRepository, binding, false)
+	middleware.MidRefresh = middleware.Auth(refreshKeys, sessionRepository, binding, true)
+	middleware.MidTwoFactor = middleware.TwoFactor(accessKeys, userRepository, binding)
+
+	// Browsers of the cookie mode send the tokens in cookies instead of the 'Authorization' header.
+	if middleware.AuthCookies = newAuthCookies(); middleware.AuthCookies != nil {
+		middleware.MidAccess = middleware.AuthCookies.Auth(middleware.CookieAccess, middleware.MidAccess)
+		middleware.MidRefresh = middleware.AuthCookies.Auth(middleware.CookieRefresh, middleware.MidRefresh)
+	}
+	middleware.MidResource = middleware.Resource(middleware.ApiKey(apiKeyRepository), middleware.MidAccess)
+
+	// The resource routes are limited per API key or user, so the clients behind one address do not share
+	// a limit. Only the public routes checking credentials or sending mails are limited per address.
+	store := newRateLimitStore()
+	middleware.MidRateLimit = middleware.RateLimit(newRateLimit(store, "api", 300, time.Minute), middleware.RateLimitByClient)
+	middleware.MidAuthRateLimit = middleware.RateLimit(newRateLimit(store, "auth", 30, time.Minute), middleware.RateLimitByIP)
 
 	// Prepare endpoints for the API.
 	handler.NewMiscHandler(app.Group(""))
-	handler.NewAuthHandler(app.Group("/auth"), authService)
+	handler.NewKeyHandler(app.Group("/.well-known"), accessKeys)
+	handler.NewAuthHandler(app.Group("/auth"), authService, sessionService)
 	handler.NewProfileHandler(app.Group("/profile"), profileService, reqMid)
-	handler.NewUserHandler(app.Group("/user"), userService, reqMid)
+	handler.NewUserHandler(app.Group("/user"), userService, sessionService, authService, reqMid)
+	handler.NewRegisterHandler(app.Group("/register"), userService)
 	handler.NewProductHandler(app.Group("/product"), productService, reqMid)
+	handler.NewApiKeyHandler(app.Group("/apikey"), apiKeyService, reqMid)
+	handler.NewAuditHandler(app.Group("/audit"), auditService)
+	handler.NewSearchHandler(app.Group("/search"), searchService)
 
 	// Prepare an endpoint for 'Not Found'.
 	app.All("*", func(c *fiber.Ctx) error {
@@ -80,6 +237,7 @@ func HandleRequests(app *fiber.App, postgresdb *gorm.DB) {
 	}
 
 	initRepositories(postgresdb)
+	initKeyrings()
 	initServices()
 	initHandelrs(app, postgresdb)
 
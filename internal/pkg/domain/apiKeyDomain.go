@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/validator"
+)
+
+const ApiKeyTableName string = "api_keys"
+
+const (
+	// ApiKeyScheme Authorization scheme of the API keys, 'Authorization: ApiKey <key>'.
+	ApiKeyScheme string = "ApiKey"
+	// apiKeyPrefix Marks the API keys, making leaked keys easy to find.
+	apiKeyPrefix string = "msd_"
+	// apiKeyShown Number of leading characters of the key stored to identify it.
+	apiKeyShown = 12
+	// scopeAll Scope action granting every action of the module.
+	scopeAll string = "*"
+)
+
+var (
+	ErrInvalidApiKey   = errors.New("invalid api key")
+	ErrInvalidScope    = errors.New("invalid api key scope")
+	ErrApiKeyForbidden = errors.New("api key grants more than the caller holds")
+)
+
+// ApiKeyFilterFields Fields the list can be filtered by, besides the search.
+var ApiKeyFilterFields = filter.Fields{
+	"id":         {Column: "id", Type: filter.Int},
+	"name":       {Column: "name", Type: filter.String},
+	"prefix":     {Column: "prefix", Type: filter.String},
+	"status":     {Column: "status", Type: filter.Bool},
+	"profile_id": {Column: "profile_id", Type: filter.Int},
+	"expires_at": {Column: "expires_at", Type: filter.Time},
+	"last_used":  {Column: "last_used", Type: filter.Time},
+	"created_at": {Column: "created_at", Type: filter.Time},
+	"updated_at": {Column: "updated_at", Type: filter.Time},
+}
+
+// ApiKeyRelations Relations embedded with 'include', the profile by default.
+var ApiKeyRelations = filter.Relations{
+	"profile": {Preload: postgre.Profile, Default: true},
+}
+
+type (
+	// ApiKey Credential of a machine, acting with the permissions of its profile limited by its
+	// scopes ('module:action' or 'module:*'), only the hash of the key is stored.
+	ApiKey struct {
+		Base
+		Name      string     `json:"name" gorm:"column:name;type:varchar(100);not null;" validate:"required,min=2"`
+		Prefix    string     `json:"prefix" gorm:"column:prefix;type:varchar(20);not null;"`
+		Hash      string     `json:"-" gorm:"column:hash;type:varchar(64);not null;unique;"`
+		ProfileID uint       `json:"-" gorm:"column:profile_id;type:bigint;not null;index;" validate:"required,min=1"`
+		Profile   *Profile   `json:"profile,omitempty"`
+		Scopes    []string   `json:"scopes" gorm:"column:scopes;type:jsonb;serializer:json;not null;"`
+		Status    bool       `json:"status" gorm:"column:status;type:bool;not null;"`
+		ExpiresAt *time.Time `json:"expires_at" gorm:"column:expires_at;"`
+		LastUsed  *time.Time `json:"last_used" gorm:"column:last_used;"`
+	}
+
+	ApiKeyRepository interface {
+		CountApiKeys(context.Context, *filter.Filter) (int64, error)
+		GetApiKeyByID(context.Context, uint) (*ApiKey, error)
+		GetApiKeyByHash(context.Context, string) (*ApiKey, error)
+		GetApiKeys(context.Context, *filter.Filter) (*[]ApiKey, error)
+		CreateApiKey(context.Context, *dto.ApiKeyInputDTO) (*ApiKey, string, error)
+		UpdateApiKey(context.Context, *ApiKey, *dto.ApiKeyInputDTO) error
+		DeleteApiKey(context.Context, *ApiKey) error
+		TouchApiKey(context.Context, *ApiKey) error
+	}
+
+	ApiKeyService interface {
+		GetApiKeyByID(context.Context, uint) (*dto.ApiKeyOutputDTO, error)
+		GetApiKeys(context.Context, *filter.Filter) (*dto.ListItemsOutputDTO, error)
+		CreateApiKey(context.Context, *User, *dto.ApiKeyInputDTO) (*dto.ApiKeyOutputDTO, error)
+		UpdateApiKey(context.Context, *User, *ApiKey, *dto.ApiKeyInputDTO) (*dto.ApiKeyOutputDTO, error)
+		DeleteApiKey(context.Context, *ApiKey) error
+	}
+)
+
+func (s *ApiKey) TableName() string {
+	return ApiKeyTableName
+}
+
+// GenerateApiKey Sets a new random key, returning it to be shown once.
+func (s *ApiKey) GenerateApiKey() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	raw := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+	s.Prefix = raw[:apiKeyShown]
+	s.Hash = HashToken(raw)
+	return raw, nil
+}
+
+// IsActive Reports whether the key is enabled and not expired.
+func (s *ApiKey) IsActive() bool {
+	return s.Status && (s.ExpiresAt == nil || s.ExpiresAt.After(time.Now()))
+}
+
+// Allowed Reports whether both the profile and the scopes of the key grant the action on the module.
+func (s *ApiKey) Allowed(module, action string) bool {
+	if s.Profile == nil || !s.Profile.Allowed(module, action) {
+		return false
+	}
+
+	return slices.Contains(s.Scopes, module+":"+action) || slices.Contains(s.Scopes, module+":"+scopeAll)
+}
+
+// GrantableBy Reports whether the user may hand out the key, only when its own profile covers the
+// profile of the key and grants every scope, so a key never raises the rights of its creator.
+func (s *ApiKey) GrantableBy(user *User) bool {
+	if user.Profile == nil || s.Profile == nil || !user.Profile.Covers(s.Profile) {
+		return false
+	}
+
+	for _, scope := range s.Scopes {
+		module, action, _ := strings.Cut(scope, ":")
+		actions := []string{action}
+		if action == scopeAll {
+			actions = Actions
+		}
+		for _, action := range actions {
+			if !user.Profile.Allowed(module, action) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Grants Returns the rights of the key as a profile, the actions granted by both its profile and its scopes.
+func (s *ApiKey) Grants() *Profile {
+	grants := &Profile{}
+	for _, module := range Modules {
+		grants.Permissions = append(grants.Permissions, Permission{
+			Module: module,
+			Read:   s.Allowed(module, ActionRead),
+			Create: s.Allowed(module, ActionCreate),
+			Update: s.Allowed(module, ActionUpdate),
+			Delete: s.Allowed(module, ActionDelete),
+		})
+	}
+
+	return grants
+}
+
+func validScope(scope string) bool {
+	module, action, found := strings.Cut(scope, ":")
+	return found && slices.Contains(Modules, module) && (action == scopeAll || slices.Contains(Actions, action))
+}
+
+func (s *ApiKey) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       s.Name,
+		"profile_id": s.ProfileID,
+		"scopes":     s.Scopes,
+		"status":     s.Status,
+		"expires_at": s.ExpiresAt,
+	}
+}
+
+func (s *ApiKey) Bind(p *dto.ApiKeyInputDTO) error {
+	if p.Name != nil {
+		s.Name = *p.Name
+	}
+	if p.ProfileID != nil {
+		s.ProfileID = *p.ProfileID
+	}
+	if p.Status != nil {
+		s.Status = *p.Status
+	}
+	if p.ExpiresAt != nil {
+		s.ExpiresAt = p.ExpiresAt
+	}
+
+	if p.Scopes != nil {
+		for _, scope := range p.Scopes {
+			if !validScope(scope) {
+				return ErrInvalidScope
+			}
+		}
+		s.Scopes = p.Scopes
+	}
+
+	if s.Scopes == nil {
+		s.Scopes = []string{}
+	}
+
+	return validator.StructValidator.Validate(s)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const AttemptTableName string = "attempts"
+
+var ErrTooManyAttempts = errors.New("too many attempts")
+
+type (
+	// Attempt Counter of the failed attempts of a key, shared by every API instance.
+	Attempt struct {
+		Key         string     `json:"-" gorm:"column:key;type:varchar(255);primarykey;"`
+		Count       int        `json:"-" gorm:"column:count;type:int;not null;default:0;"`
+		LockedUntil *time.Time `json:"-" gorm:"column:locked_until;"`
+		UpdatedAt   time.Time  `json:"-" gorm:"column:updated_at;not null;"`
+	}
+
+	// AttemptPolicy Counts the attempts made within 'Window' of each other, from 'Limit' attempts on
+	// the key is locked for 'Lock', doubling on every new attempt up to 'MaxLock'.
+	AttemptPolicy struct {
+		Limit   int
+		Window  time.Duration
+		Lock    time.Duration
+		MaxLock time.Duration
+	}
+
+	// LockedError Returned while a key is locked, 'Until' tells the client when to retry.
+	LockedError struct {
+		Until time.Time
+	}
+
+	AttemptRepository interface {
+		GetAttempt(context.Context, string) (*Attempt, error)
+		RegisterAttempt(context.Context, string, *AttemptPolicy) (*Attempt, error)
+		ResetAttempts(context.Context, ...string) error
+	}
+)
+
+func (s *Attempt) TableName() string {
+	return AttemptTableName
+}
+
+// Locked Returns the lockout error while the key is locked.
+func (s *Attempt) Locked() error {
+	if s.LockedUntil != nil && s.LockedUntil.After(time.Now()) {
+		return &LockedError{Until: *s.LockedUntil}
+	}
+
+	return nil
+}
+
+// LockFor Returns how long the key must be locked after reaching the count, zero under the limit.
+func (s *AttemptPolicy) LockFor(count int) time.Duration {
+	if s.Limit < 1 || count < s.Limit {
+		return 0
+	}
+
+	lock := s.Lock
+	for i := s.Limit; i < count && lock < s.MaxLock; i++ {
+		lock *= 2
+	}
+
+	return min(lock, s.MaxLock)
+}
+
+func (e *LockedError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// LoginMailKey Attempts key of the logins to an account.
+func LoginMailKey(mail string) string {
+	return "login:mail:" + strings.ToLower(strings.TrimSpace(mail))
+}
+
+// LoginIPKey Attempts key of the logins from an address.
+func LoginIPKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// RegisterIPKey Attempts key of the sign-ups from an address.
+func RegisterIPKey(ip string) string {
+	return "register:ip:" + ip
+}
+
+// RegisterMailKey Attempts key of the sign-ups to a mail, limiting the mails sent to it.
+func RegisterMailKey(mail string) string {
+	return "register:mail:" + strings.ToLower(strings.TrimSpace(mail))
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	httphelper "github.com/Duncan-Kiragu/Msaada-Backend/pkg/http-helper"
+)
+
+const AuditLogTableName string = "audit_logs"
+
+// AuditFilterFields Fields the list can be filtered by, besides the search.
+var AuditFilterFields = filter.Fields{
+	"id":              {Column: "id", Type: filter.Int},
+	"actor_id":        {Column: "actor_id", Type: filter.Int},
+	"api_key_id":      {Column: "api_key_id", Type: filter.Int},
+	"impersonated_id": {Column: "impersonated_id", Type: filter.Int},
+	"action":          {Column: "action", Type: filter.String},
+	"entity":          {Column: "entity", Type: filter.String},
+	"entity_id":       {Column: "entity_id", Type: filter.Int},
+	"request_id":      {Column: "request_id", Type: filter.String},
+	"ip":              {Column: "ip", Type: filter.String},
+	"created_at":      {Column: "created_at", Type: filter.Time},
+}
+
+const (
+	AuditCreate      string = "create"
+	AuditUpdate      string = "update"
+	AuditDelete      string = "delete"
+	AuditLogin       string = "login"
+	AuditLoginFailed string = "login_failed"
+	AuditImpersonate string = "impersonate"
+)
+
+// auditMasked Columns whose values are never written to the audit log, only whether they changed.
+var auditMasked = []string{"password", "token", "hash", "totp_secret"}
+
+// auditMask Value recorded in place of the masked columns.
+const auditMask = "***"
+
+type (
+	// AuditChange Values of a column before and after a mutation.
+	AuditChange struct {
+		Before interface{} `json:"before"`
+		After  interface{} `json:"after"`
+	}
+
+	// AuditLog Record of a mutation or login, rows are never updated nor deleted (enforced by a trigger).
+	AuditLog struct {
+		Id       uint  `json:"id" gorm:"primarykey"`
+		ActorID  *uint `json:"actor_id" gorm:"column:actor_id;index;"`
+		ApiKeyID *uint `json:"api_key_id" gorm:"column:api_key_id;index;"`
+		// ImpersonatedID User whose session the actor was impersonating.
+		ImpersonatedID *uint                  `json:"impersonated_id" gorm:"column:impersonated_id;index;"`
+		IP             string                 `json:"ip" gorm:"column:ip;type:varchar(45);"`
+		RequestID      string                 `json:"request_id" gorm:"column:request_id;type:varchar(64);index;"`
+		Action         string                 `json:"action" gorm:"column:action;type:varchar(20);not null;index;"`
+		Entity         string                 `json:"entity" gorm:"column:entity;type:varchar(50);not null;index:idx_audit_entity;"`
+		EntityID       *uint                  `json:"entity_id" gorm:"column:entity_id;index:idx_audit_entity;"`
+		Diff           map[string]AuditChange `json:"diff" gorm:"column:diff;type:jsonb;serializer:json;not null;"`
+		CreatedAt      time.Time              `json:"created_at" gorm:"column:created_at;not null;index;"`
+	}
+
+	AuditRepository interface {
+		CountAuditLogs(context.Context, *filter.Filter) (int64, error)
+		GetAuditLogs(context.Context, *filter.Filter) (*[]AuditLog, error)
+		CreateAuditLog(context.Context, *AuditLog) error
+	}
+
+	AuditService interface {
+		GetAuditLogs(context.Context, *filter.Filter) (*dto.ListItemsOutputDTO, error)
+	}
+)
+
+func (s *AuditLog) TableName() string {
+	return AuditLogTableName
+}
+
+// NewAuditLog Creates the record of the action on the entity, the actor, address and request ID are
+// read from the request context, where the middlewares store them.
+func NewAuditLog(ctx context.Context, action, entity string, entityID uint, before, after map[string]interface{}) *AuditLog {
+	log := &AuditLog{
+		Action: action,
+		Entity: entity,
+		Diff:   AuditDiff(before, after),
+	}
+
+	if entityID != 0 {
+		log.EntityID = &entityID
+	}
+
+	if user, ok := ctx.Value(httphelper.LocalUser).(*User); ok && user != nil {
+		log.ActorID = &user.Id
+
+		// The impersonating admin is the actor of what is done in the session of the user.
+		if impersonator, ok := ctx.Value(httphelper.LocalImpersonator).(uint); ok {
+			log.ActorID, log.ImpersonatedID = &impersonator, &user.Id
+		}
+	}
+
+	if apiKey, ok := ctx.Value(httphelper.LocalApiKey).(*ApiKey); ok && apiKey != nil {
+		log.ApiKeyID = &apiKey.Id
+	}
+
+	log.IP, _ = ctx.Value(httphelper.LocalIP).(string)
+	log.RequestID, _ = ctx.Value(httphelper.LocalRequestID).(string)
+	return log
+}
+
+// AuditDiff Returns the columns that changed, the values of the masked columns are hidden.
+func AuditDiff(before, after map[string]interface{}) map[string]AuditChange {
+	diff := map[string]AuditChange{}
+
+	for column, value := range after {
+		if old, found := before[column]; !found || !reflect.DeepEqual(old, value) {
+			diff[column] = AuditChange{Before: before[column], After: value}
+		}
+	}
+
+	for column, old := range before {
+		if _, found := after[column]; !found {
+			diff[column] = AuditChange{Before: old}
+		}
+	}
+
+	for column, change := range diff {
+		if slices.Contains(auditMasked, column) {
+			if change.Before != nil {
+				change.Before = auditMask
+			}
+			if change.After != nil {
+				change.After = auditMask
+			}
+			diff[column] = change
+		}
+	}
+
+	return diff
+}
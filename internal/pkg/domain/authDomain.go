@@ -11,8 +11,16 @@ var ErrInvalidIpAssociation = errors.New("invalid ip source")
 
 type (
 	AuthService interface {
-		Login(context.Context, *dto.AuthInputDTO, string) (*dto.AuthOutputDTO, error)
-		Refresh(*User, string) *dto.AuthOutputDTO
+		Login(context.Context, *dto.AuthInputDTO, string, string) (*dto.AuthOutputDTO, error)
+		Refresh(context.Context, *Session, string) (*dto.AuthOutputDTO, error)
 		Me(*User) *dto.UserOutputDTO
+		SetupTwoFactor(context.Context, *User) (*dto.TwoFactorSetupOutputDTO, error)
+		EnableTwoFactor(context.Context, *User, *dto.TwoFactorInputDTO) (*dto.RecoveryCodesOutputDTO, error)
+		DisableTwoFactor(context.Context, *User, *dto.TwoFactorInputDTO) error
+		VerifyTwoFactor(context.Context, *User, *Claims, *dto.TwoFactorInputDTO, string, string) (*dto.AuthOutputDTO, error)
+		OidcLogin(context.Context, bool) (string, error)
+		OidcCallback(context.Context, string, string, string, string) (*dto.AuthOutputDTO, error)
+		ChangePassword(context.Context, *User, *Session, *dto.ChangePasswordInputDTO, string, string) (*dto.AuthOutputDTO, error)
+		Impersonate(context.Context, *User, *User, string, string) (*dto.AuthOutputDTO, error)
 	}
 )
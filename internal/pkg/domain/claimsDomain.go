@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	TokenAccess    string = "access"
+	TokenRefresh   string = "refresh"
+	TokenTwoFactor string = "2fa"
+
+	// DefaultIssuer Issuer of the tokens when 'JWT_ISSUER' is not set.
+	DefaultIssuer string = "msaada-backend"
+	// DefaultAudience Audience of the tokens when 'JWT_AUDIENCE' is not set.
+	DefaultAudience string = "msaada-api"
+)
+
+var ErrInvalidTokenType = errors.New("invalid token type")
+
+// Claims Claims of the tokens issued by the API, 'Type' keeps a token from being accepted in place of another.
+type Claims struct {
+	jwt.RegisteredClaims
+	Type    string `json:"typ"`
+	Session string `json:"sid,omitempty"`
+	IP      string `json:"ip"`
+	// Persistent Whether the session created after the second factor never expires.
+	Persistent bool `json:"persistent,omitempty"`
+	// Actor Admin acting as the subject, only set on the impersonation tokens (RFC 8693).
+	Actor *Actor `json:"act,omitempty"`
+}
+
+// Actor Identifies the admin impersonating the subject of the token.
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// TokenIssuer Returns the 'iss' claim of the tokens.
+func TokenIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+
+	return DefaultIssuer
+}
+
+// TokenAudience Returns the 'aud' claim of the tokens.
+func TokenAudience() string {
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		return audience
+	}
+
+	return DefaultAudience
+}
+
+// NewClaims Returns the claims of a token of the user, its expiration is set by the caller.
+func NewClaims(user *User, tokenType, ip, tokenID string) *Claims {
+	now := time.Now()
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   strconv.FormatUint(uint64(user.Id), 10),
+			Issuer:    TokenIssuer(),
+			Audience:  jwt.ClaimStrings{TokenAudience()},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		Type: tokenType,
+		IP:   ip,
+	}
+
+	return claims
+}
+
+// ParseClaims Parses the token, validating its signature, registered claims and type.
+func ParseClaims(token string, keyfunc jwt.Keyfunc, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(TokenIssuer()),
+		jwt.WithAudience(TokenAudience()),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	if claims.ID == "" {
+		return nil, jwt.ErrTokenInvalidId
+	}
+
+	return claims, nil
+}
+
+// UserID Returns the user ID of the 'sub' claim.
+func (s *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(s.Subject, 10, 0)
+	if err != nil {
+		return 0, jwt.ErrTokenInvalidSubject
+	}
+
+	return uint(id), nil
+}
+
+// ActorID Returns the user ID of the impersonating admin, zero when the token is not an impersonation.
+func (s *Claims) ActorID() (uint, error) {
+	if s.Actor == nil {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(s.Actor.Subject, 10, 0)
+	if err != nil || id == 0 {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+
+	return uint(id), nil
+}
+
+// newTokenID Returns a random 'jti'.
+func newTokenID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const OidcStateTableName string = "oidc_states"
+
+// OidcStateLife Time to complete the login at the identity provider.
+const OidcStateLife = 10 * time.Minute
+
+var (
+	ErrOidcDisabled         = errors.New("oidc login disabled")
+	ErrInvalidOidcState     = errors.New("invalid oidc state")
+	ErrOidcEmailNotVerified = errors.New("oidc email not verified")
+)
+
+type (
+	// OidcState Login started at the identity provider, found by the hash of the state sent along
+	// and consumed by the callback, keeping the nonce and the PKCE verifier on the server.
+	OidcState struct {
+		Hash      string    `json:"-" gorm:"column:hash;type:varchar(64);primaryKey;"`
+		Nonce     string    `json:"-" gorm:"column:nonce;type:varchar(64);not null;"`
+		Verifier  string    `json:"-" gorm:"column:verifier;type:varchar(128);not null;"`
+		Expire    bool      `json:"-" gorm:"column:expire;type:bool;not null;"`
+		ExpiresAt time.Time `json:"-" gorm:"column:expires_at;not null;index;"`
+	}
+
+	OidcStateRepository interface {
+		CreateOidcState(context.Context, *OidcState) error
+		UseOidcState(context.Context, string) (*OidcState, error)
+	}
+)
+
+func (s *OidcState) TableName() string {
+	return OidcStateTableName
+}
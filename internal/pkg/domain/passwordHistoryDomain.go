@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/hasher"
+)
+
+const PasswordHistoryTableName string = "password_histories"
+
+var ErrPasswordReused = errors.New("password recently used")
+
+// PasswordHistory Hash of a password set by the user, kept to prevent its reuse.
+type PasswordHistory struct {
+	Id        uint      `json:"-" gorm:"primarykey"`
+	UserID    uint      `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+	User      *User     `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Hash      string    `json:"-" gorm:"column:hash;type:varchar(255);not null;"`
+	CreatedAt time.Time `json:"-" gorm:"column:created_at;not null;"`
+}
+
+func (s *PasswordHistory) TableName() string {
+	return PasswordHistoryTableName
+}
+
+// Matches Reports whether the password is the one stored in the history.
+func (s *PasswordHistory) Matches(password string) bool {
+	return hasher.Verify(s.Hash, password)
+}
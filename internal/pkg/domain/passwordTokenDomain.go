@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+const PasswordTokenTableName string = "password_tokens"
+
+const (
+	TokenInvite string = "invite"
+	TokenReset  string = "reset"
+	TokenVerify string = "verify"
+)
+
+var ErrInvalidPasswordToken = errors.New("invalid password token")
+
+type (
+	// PasswordToken One time token sent by mail, only its hash is stored.
+	PasswordToken struct {
+		Base
+		UserID    uint       `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+		User      *User      `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+		Kind      string     `json:"kind" gorm:"column:kind;type:varchar(20);not null;"`
+		Hash      string     `json:"-" gorm:"column:hash;type:varchar(64);not null;unique;"`
+		ExpiresAt time.Time  `json:"expires_at" gorm:"column:expires_at;not null;"`
+		UsedAt    *time.Time `json:"used_at" gorm:"column:used_at;"`
+	}
+
+	PasswordTokenRepository interface {
+		CreatePasswordToken(context.Context, *PasswordToken) error
+		CheckPasswordToken(context.Context, uint, string, ...string) error
+		UsePasswordToken(context.Context, uint, string, ...string) error
+	}
+)
+
+func (s *PasswordToken) TableName() string {
+	return PasswordTokenTableName
+}
+
+// HashToken Returns the stored representation of a raw token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewPasswordToken Creates a token of the kind for the user, returning it along with the raw value to be sent.
+func NewPasswordToken(user *User, kind string, life time.Duration) (*PasswordToken, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return &PasswordToken{
+		UserID:    user.Id,
+		Kind:      kind,
+		Hash:      HashToken(token),
+		ExpiresAt: time.Now().Add(life),
+	}, token, nil
+}
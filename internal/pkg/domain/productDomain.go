@@ -10,10 +10,21 @@ import (
 
 const ProductTableName string = "product"
 
+// ProductFilterFields Fields the list can be filtered by, besides the search.
+var ProductFilterFields = filter.Fields{
+	"id":         {Column: "id", Type: filter.Int},
+	"name":       {Column: "name", Type: filter.String},
+	"created_at": {Column: "created_at", Type: filter.Time},
+	"updated_at": {Column: "updated_at", Type: filter.Time},
+}
+
 type (
 	Product struct {
 		Base
 		Name string `json:"name" gorm:"column:name;type:varchar(100);unique;index;not null;" validate:"required,min=2"`
+
+		// Headline Highlighted match of the full text search, only read by the searches.
+		Headline string `json:"-" gorm:"column:headline;->;-:migration"`
 	}
 
 	ProductRepository interface {
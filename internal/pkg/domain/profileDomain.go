@@ -2,32 +2,83 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/validator"
 )
 
-const ProfileTableName string = "profiles"
+const (
+	ProfileTableName    string = "profiles"
+	PermissionTableName string = "permissions"
+)
+
+const (
+	ModuleUser    string = "user"
+	ModuleProfile string = "profile"
+	ModuleProduct string = "product"
+	ModuleApiKey  string = "apikey"
+	ModuleAudit   string = "audit"
+
+	ActionRead   string = "read"
+	ActionCreate string = "create"
+	ActionUpdate string = "update"
+	ActionDelete string = "delete"
+)
+
+// Modules Every module that can be granted to a profile, new modules must be appended here.
+var Modules = []string{ModuleUser, ModuleProfile, ModuleProduct, ModuleApiKey, ModuleAudit}
+
+// Actions Every action that can be granted on a module.
+var Actions = []string{ActionRead, ActionCreate, ActionUpdate, ActionDelete}
+
+var (
+	ErrInvalidModule  = errors.New("invalid permission module")
+	ErrGrantForbidden = errors.New("grants more than the caller holds")
+)
+
+// ProfileFilterFields Fields the list can be filtered by, besides the search.
+var ProfileFilterFields = filter.Fields{
+	"id":         {Column: "id", Type: filter.Int},
+	"name":       {Column: "name", Type: filter.String},
+	"two_factor": {Column: "two_factor", Type: filter.Bool},
+	"created_at": {Column: "created_at", Type: filter.Time},
+	"updated_at": {Column: "updated_at", Type: filter.Time},
+}
+
+// ProfileRelations Relations embedded with 'include', the permissions by default.
+var ProfileRelations = filter.Relations{
+	"permissions": {Preload: postgre.Permissions, Default: true},
+}
 
 type (
-	Permissions struct {
-		Id            uint `json:"-" gorm:"primarykey"`
-		ProfileID     uint `json:"-" gorm:"column:profile_id;unique;"`
-		UserModule    bool `json:"user_module" gorm:"column:user;type:bool;not null;"`
-		ProfileModule bool `json:"profile_module" gorm:"column:profile;type:bool;not null;"`
-		ProductModule bool `json:"product_module" gorm:"column:product;type:bool;not null;"`
+	Permission struct {
+		Id        uint   `json:"-" gorm:"primarykey"`
+		ProfileID uint   `json:"-" gorm:"column:profile_id;not null;uniqueIndex:idx_permission_module;"`
+		Module    string `json:"module" gorm:"column:module;type:varchar(50);not null;uniqueIndex:idx_permission_module;"`
+		Read      bool   `json:"read" gorm:"column:read;type:bool;not null;"`
+		Create    bool   `json:"create" gorm:"column:create;type:bool;not null;"`
+		Update    bool   `json:"update" gorm:"column:update;type:bool;not null;"`
+		Delete    bool   `json:"delete" gorm:"column:delete;type:bool;not null;"`
 	}
 
 	Profile struct {
 		Base
-		Name        string      `json:"name" gorm:"column:name;type:varchar(100);unique;not null;" validate:"required,min=4"`
-		Permissions Permissions `json:"permissions" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+		Name        string       `json:"name" gorm:"column:name;type:varchar(100);unique;not null;" validate:"required,min=4"`
+		TwoFactor   bool         `json:"two_factor" gorm:"column:two_factor;type:bool;not null;default:false;"`
+		Permissions []Permission `json:"permissions,omitempty" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+		// Headline Highlighted match of the full text search, only read by the searches.
+		Headline string `json:"-" gorm:"column:headline;->;-:migration"`
 	}
 
 	ProfileRepository interface {
 		CountProfiles(context.Context, *filter.Filter) (int64, error)
 		GetProfileByID(context.Context, uint) (*Profile, error)
+		GetProfileByName(context.Context, string) (*Profile, error)
 		GetProfiles(context.Context, *filter.Filter) (*[]Profile, error)
 		CreateProfile(context.Context, *dto.ProfileInputDTO) (*Profile, error)
 		UpdateProfile(context.Context, *Profile, *dto.ProfileInputDTO) error
@@ -37,22 +88,100 @@ type (
 	ProfileService interface {
 		GetProfileByID(context.Context, uint) (*dto.ProfileOutputDTO, error)
 		GetProfiles(context.Context, *filter.Filter) (*dto.ListItemsOutputDTO, error)
-		CreateProfile(context.Context, *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error)
-		UpdateProfile(context.Context, *Profile, *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error)
+		CreateProfile(context.Context, *Profile, *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error)
+		UpdateProfile(context.Context, *Profile, *Profile, *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error)
 		DeleteProfile(context.Context, *Profile) error
+		GenerateProfileOutputDTO(*Profile) *dto.ProfileOutputDTO
 	}
 )
 
+func (s *Permission) TableName() string {
+	return PermissionTableName
+}
+
+func (s *Permission) Allows(action string) bool {
+	switch action {
+	case ActionRead:
+		return s.Read
+	case ActionCreate:
+		return s.Create
+	case ActionUpdate:
+		return s.Update
+	case ActionDelete:
+		return s.Delete
+	default:
+		return false
+	}
+}
+
 func (s *Profile) TableName() string {
 	return ProfileTableName
 }
 
-func (s *Permissions) ToMap() *map[string]interface{} {
-	return &map[string]interface{}{
-		"user":    s.UserModule,
-		"profile": s.ProfileModule,
-		"product": s.ProductModule,
+// Allowed Reports whether the profile grants the action on the module.
+func (s *Profile) Allowed(module, action string) bool {
+	for _, permission := range s.Permissions {
+		if permission.Module == module {
+			return permission.Allows(action)
+		}
+	}
+
+	return false
+}
+
+// Covers Reports whether the profile grants every action granted by the other one.
+func (s *Profile) Covers(other *Profile) bool {
+	for _, permission := range other.Permissions {
+		for _, action := range Actions {
+			if permission.Allows(action) && !s.Allowed(permission.Module, action) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// CanGrant Reports whether the profile may grant the other one, only when it grants every action
+// of the other, so nobody hands out more than they hold.
+func (s *Profile) CanGrant(other *Profile) bool {
+	return s != nil && other != nil && s.Covers(other)
+}
+
+func (s *Profile) permission(module string) *Permission {
+	for i := range s.Permissions {
+		if s.Permissions[i].Module == module {
+			return &s.Permissions[i]
+		}
 	}
+
+	s.Permissions = append(s.Permissions, Permission{ProfileID: s.Id, Module: module})
+	return &s.Permissions[len(s.Permissions)-1]
+}
+
+func (s *Profile) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       s.Name,
+		"two_factor": s.TwoFactor,
+	}
+}
+
+// AuditMap Returns the columns along with the actions granted on each module.
+func (s *Profile) AuditMap() map[string]interface{} {
+	mapped := s.ToMap()
+
+	permissions := map[string][]string{}
+	for _, permission := range s.Permissions {
+		permissions[permission.Module] = []string{}
+		for _, action := range Actions {
+			if permission.Allows(action) {
+				permissions[permission.Module] = append(permissions[permission.Module], action)
+			}
+		}
+	}
+	mapped["permissions"] = permissions
+
+	return mapped
 }
 
 func (s *Profile) Bind(p *dto.ProfileInputDTO) error {
@@ -60,16 +189,28 @@ func (s *Profile) Bind(p *dto.ProfileInputDTO) error {
 		s.Name = *p.Name
 	}
 
-	if p.Permissions.UserModule != nil {
-		s.Permissions.UserModule = *p.Permissions.UserModule
+	if p.TwoFactor != nil {
+		s.TwoFactor = *p.TwoFactor
 	}
 
-	if p.Permissions.ProfileModule != nil {
-		s.Permissions.ProfileModule = *p.Permissions.ProfileModule
-	}
+	for module, grant := range p.Permissions {
+		if !slices.Contains(Modules, module) {
+			return ErrInvalidModule
+		}
 
-	if p.Permissions.ProductModule != nil {
-		s.Permissions.ProductModule = *p.Permissions.ProductModule
+		permission := s.permission(module)
+		if grant.Read != nil {
+			permission.Read = *grant.Read
+		}
+		if grant.Create != nil {
+			permission.Create = *grant.Create
+		}
+		if grant.Update != nil {
+			permission.Update = *grant.Update
+		}
+		if grant.Delete != nil {
+			permission.Delete = *grant.Delete
+		}
 	}
 
 	return validator.StructValidator.Validate(s)
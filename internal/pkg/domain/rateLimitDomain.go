@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const RateLimitTableName string = "rate_limits"
+
+type (
+	// RateLimit Requests counted for a key in its current window, shared by every API instance.
+	RateLimit struct {
+		Key     string    `json:"-" gorm:"column:key;type:varchar(255);primarykey;"`
+		Count   int       `json:"-" gorm:"column:count;type:int;not null;default:0;"`
+		ResetAt time.Time `json:"-" gorm:"column:reset_at;not null;index;"`
+	}
+
+	// RateLimitRepository Postgres store of the rate limiters.
+	RateLimitRepository interface {
+		Hit(context.Context, string, time.Duration) (int, time.Time, error)
+	}
+)
+
+func (s *RateLimit) TableName() string {
+	return RateLimitTableName
+}
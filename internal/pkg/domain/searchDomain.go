@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+)
+
+// SearchModules Modules searched by the global search, the ones with a full text search vector.
+var SearchModules = []string{ModuleUser, ModuleProfile, ModuleProduct}
+
+type SearchService interface {
+	Search(context.Context, *filter.Filter, []string) (*dto.SearchOutputDTO, error)
+}
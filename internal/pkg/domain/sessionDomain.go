@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
+)
+
+const SessionTableName string = "sessions"
+
+const (
+	// defaultImpersonationLife Life of the impersonation sessions when 'IMPERSONATION_TOKEN_EXPIRE' is not set.
+	defaultImpersonationLife = 15 * time.Minute
+	// defaultAccessTokenLife Life of the access tokens when 'ACCESS_TOKEN_EXPIRE' is not set.
+	defaultAccessTokenLife = 24 * time.Hour
+)
+
+var (
+	ErrInvalidSession         = errors.New("invalid session")
+	ErrTokenReused            = errors.New("refresh token reused")
+	ErrImpersonationForbidden = errors.New("user can not be impersonated")
+	ErrImpersonationDenied    = errors.New("not allowed while impersonating")
+)
+
+type (
+	// Session A login of a user, the refresh token family issued by it shares the session token
+	// and only the refresh ID of the last rotation is accepted.
+	Session struct {
+		Base
+		UserID    uint       `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+		User      *User      `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+		Token     string     `json:"-" gorm:"column:token;type:uuid;not null;unique;index;"`
+		RefreshID string     `json:"-" gorm:"column:refresh_id;type:uuid;not null;"`
+		IP        string     `json:"ip" gorm:"column:ip;type:varchar(45);not null;"`
+		Agent     string     `json:"agent" gorm:"column:agent;type:varchar(255);"`
+		Expire    bool       `json:"-" gorm:"column:expire;type:bool;not null;"`
+		ExpiresAt *time.Time `json:"-" gorm:"column:expires_at;"`
+		LastSeen  time.Time  `json:"last_seen" gorm:"column:last_seen;not null;"`
+		RevokedAt *time.Time `json:"-" gorm:"column:revoked_at;index;"`
+
+		// RefreshKeyID Key that signed the last refresh token of the session, kept while the session is active.
+		RefreshKeyID string `json:"-" gorm:"column:refresh_key_id;type:varchar(64);index;"`
+
+		// ImpersonatorID Admin that opened the session to act as the user, nil for the user own logins.
+		ImpersonatorID *uint `json:"-" gorm:"column:impersonator_id;index;"`
+		Impersonator   *User `json:"-" gorm:"foreignKey:ImpersonatorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	}
+
+	SessionRepository interface {
+		GetSessionByToken(context.Context, string) (*Session, error)
+		GetUserSessions(context.Context, uint) (*[]Session, error)
+		CreateSession(context.Context, *Session) error
+		RotateSession(context.Context, *Session, string) error
+		TouchSession(context.Context, *Session) error
+		RevokeSession(context.Context, *Session) error
+		RevokeUserSession(context.Context, uint, uint) error
+		RevokeUserSessions(context.Context, uint) error
+	}
+
+	SessionService interface {
+		GetUserSessions(context.Context, *User, *Session) (*[]dto.SessionOutputDTO, error)
+		RevokeSession(context.Context, *Session) error
+		RevokeUserSession(context.Context, *User, uint) error
+		RevokeUserSessions(context.Context, *User) error
+	}
+)
+
+func (s *Session) TableName() string {
+	return SessionTableName
+}
+
+// IsActive Reports whether the session was neither revoked nor expired.
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(time.Now()))
+}
+
+// AccessTokenLife Returns the life of the access tokens, read in minutes from 'ACCESS_TOKEN_EXPIRE'. The
+// access tokens always expire, even those of the sessions that never do, so the retired keys can be dropped.
+func AccessTokenLife() time.Duration {
+	if life, err := helpers.DurationFromString(os.Getenv("ACCESS_TOKEN_EXPIRE"), time.Minute); err == nil {
+		return life
+	}
+
+	return defaultAccessTokenLife
+}
+
+// ImpersonationLife Returns the life of the impersonation sessions, read in minutes from 'IMPERSONATION_TOKEN_EXPIRE'.
+func ImpersonationLife() time.Duration {
+	if life, err := helpers.DurationFromString(os.Getenv("IMPERSONATION_TOKEN_EXPIRE"), time.Minute); err == nil {
+		return life
+	}
+
+	return defaultImpersonationLife
+}
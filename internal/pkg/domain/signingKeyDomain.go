@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+)
+
+const SigningKeyTableName string = "signing_keys"
+
+const (
+	KeyAccess  string = "access"
+	KeyRefresh string = "refresh"
+
+	// SigningKeyRefresh Interval in which the instances reload the signing keys.
+	SigningKeyRefresh = time.Minute
+)
+
+type (
+	// SigningKey RSA key of a token type, the current one signs new tokens and the retired ones
+	// keep verifying them until 'ExpiresAt', or while an active session references them.
+	SigningKey struct {
+		Id        string     `json:"-" gorm:"column:id;type:varchar(64);primarykey;"`
+		Use       string     `json:"-" gorm:"column:use;type:varchar(20);not null;index;"`
+		Private   string     `json:"-" gorm:"column:private;type:text;not null;"`
+		Current   bool       `json:"-" gorm:"column:current;type:bool;not null;default:false;"`
+		ExpiresAt *time.Time `json:"-" gorm:"column:expires_at;"`
+		CreatedAt time.Time  `json:"-" gorm:"column:created_at;not null;"`
+	}
+
+	// TokenSigner Signs the claims of the tokens issued by the API.
+	TokenSigner interface {
+		Sign(jwt.Claims) (string, error)
+		KeyID() string
+	}
+
+	SigningKeyRepository interface {
+		GetSigningKeys(context.Context, string) ([]SigningKey, error)
+		ImportSigningKey(context.Context, *SigningKey) error
+		RotateSigningKey(context.Context, *SigningKey, time.Time) error
+	}
+)
+
+func (s *SigningKey) TableName() string {
+	return SigningKeyTableName
+}
+
+// NewSigningKey Stores the key for the token type.
+func NewSigningKey(use string, key *keyring.Key) *SigningKey {
+	return &SigningKey{
+		Id:      key.ID,
+		Use:     use,
+		Private: string(key.EncodePrivate()),
+	}
+}
+
+// Key Parses the stored key.
+func (s *SigningKey) Key() (*keyring.Key, error) {
+	return keyring.ParseKey([]byte(s.Private))
+}
+
+// SigningKeySource Loads the keyring of the token type from the repository.
+func SigningKeySource(repo SigningKeyRepository, use string) keyring.Source {
+	return func(ctx context.Context) ([]*keyring.Key, error) {
+		stored, err := repo.GetSigningKeys(ctx, use)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]*keyring.Key, 0, len(stored))
+		for _, signingKey := range stored {
+			key, err := signingKey.Key()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+
+		return keys, nil
+	}
+}
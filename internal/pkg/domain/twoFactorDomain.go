@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	RecoveryCodeTableName  string = "recovery_codes"
+	UsedTwoFactorTableName string = "used_two_factor_tokens"
+)
+
+// recoveryCodes Number of recovery codes generated when two-factor authentication is enabled.
+const recoveryCodes = 10
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorRequired    = errors.New("two-factor authentication required by profile")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+)
+
+type (
+	// RecoveryCode Single use code that replaces the TOTP code when the authenticator is lost.
+	RecoveryCode struct {
+		Id     uint       `json:"-" gorm:"primarykey"`
+		UserID uint       `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+		User   *User      `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+		Hash   string     `json:"-" gorm:"column:hash;type:varchar(64);not null;"`
+		UsedAt *time.Time `json:"-" gorm:"column:used_at;"`
+	}
+
+	// UsedTwoFactor ID of a two-factor token already exchanged for a session, kept until the token
+	// expires so that each token completes a single login.
+	UsedTwoFactor struct {
+		TokenID   string    `json:"-" gorm:"column:token_id;type:varchar(64);primaryKey;"`
+		ExpiresAt time.Time `json:"-" gorm:"column:expires_at;not null;index;"`
+	}
+
+	TwoFactorRepository interface {
+		SetTwoFactorSecret(context.Context, *User, string) error
+		EnableTwoFactor(context.Context, *User, []RecoveryCode) error
+		DisableTwoFactor(context.Context, *User) error
+		UseRecoveryCode(context.Context, uint, string) error
+		UseTotpStep(context.Context, *User, int64) error
+		UseTwoFactorToken(context.Context, string, time.Time) error
+	}
+)
+
+func (s *RecoveryCode) TableName() string {
+	return RecoveryCodeTableName
+}
+
+func (s *UsedTwoFactor) TableName() string {
+	return UsedTwoFactorTableName
+}
+
+// NormalizeRecoveryCode Removes the formatting of a typed recovery code.
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// NewRecoveryCodes Generates the recovery codes of the user, returning them along with the raw values to be shown once.
+func NewRecoveryCodes(user *User) ([]RecoveryCode, []string, error) {
+	codes, raws := make([]RecoveryCode, recoveryCodes), make([]string, recoveryCodes)
+	for i := range codes {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(random))[:10]
+		raws[i] = raw[:5] + "-" + raw[5:]
+		codes[i] = RecoveryCode{UserID: user.Id, Hash: HashToken(raw)}
+	}
+
+	return codes, raws, nil
+}
@@ -2,21 +2,43 @@ package domain
 
 import (
 	"context"
-	"encoding/base64"
-	"fmt"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
-	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/helpers"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/hasher"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/validator"
 )
 
 const UserTableName string = "users"
 
+var ErrUserNotVerified = errors.New("user mail not verified")
+
+// UserFilterFields Fields the list can be filtered by, besides the search.
+var UserFilterFields = filter.Fields{
+	"id":         {Column: UserTableName + ".id", Type: filter.Int},
+	"name":       {Column: UserTableName + ".name", Type: filter.String},
+	"mail":       {Column: UserTableName + ".mail", Type: filter.String},
+	"status":     {Column: UserTableName + ".status", Type: filter.Bool},
+	"new":        {Column: UserTableName + ".new", Type: filter.Bool},
+	"created_at": {Column: UserTableName + ".created_at", Type: filter.Time},
+	"updated_at": {Column: UserTableName + ".updated_at", Type: filter.Time},
+}
+
+// UserRelations Relations embedded with 'include', the profile and its permissions by default.
+var UserRelations = filter.Relations{
+	"profile":             {Preload: postgre.Profile, Default: true},
+	"profile.permissions": {Preload: postgre.ProfilePermission, Default: true},
+}
+
+// TwoFactorTokenLife Time to complete the login with the second factor.
+const TwoFactorTokenLife = 5 * time.Minute
+
 type (
 	User struct {
 		Base
@@ -29,6 +51,17 @@ type (
 		Password  *string  `json:"-" gorm:"column:password;type:varchar(255);"`
 		Profile   *Profile `json:"profile,omitempty"`
 		Expire    bool     `json:"-" gorm:"-"`
+
+		TotpSecret  *string `json:"-" gorm:"column:totp_secret;type:varchar(64);"`
+		TotpEnabled bool    `json:"-" gorm:"column:totp_enabled;type:bool;not null;default:false;"`
+		// TotpStep Time step of the last TOTP code accepted, the codes of this step or before are refused.
+		TotpStep int64 `json:"-" gorm:"column:totp_step;type:bigint;not null;default:0;"`
+
+		// VerifyPending Marks the self registered users that did not verify their mail yet.
+		VerifyPending bool `json:"-" gorm:"column:verify_pending;type:bool;not null;default:false;"`
+
+		// Headline Highlighted match of the full text search, only read by the searches.
+		Headline string `json:"-" gorm:"column:headline;->;-:migration"`
 	}
 
 	UserRepository interface {
@@ -42,6 +75,10 @@ type (
 		DeleteUser(context.Context, *User) error
 		ResetUserPassword(context.Context, *User) error
 		SetUserPassword(context.Context, *User, *dto.PasswordInputDTO) error
+		GetPasswordHistory(context.Context, uint, int) ([]PasswordHistory, error)
+		RegisterUser(context.Context, *dto.RegisterInputDTO, uint) (*User, error)
+		VerifyUser(context.Context, *User, bool) error
+		RehashUserPassword(context.Context, *User, string) error
 	}
 
 	UserService interface {
@@ -49,11 +86,16 @@ type (
 		GetUsers(context.Context, *filter.UserFilter) (*dto.ListItemsOutputDTO, error)
 		GetUserByMail(context.Context, string) (*User, error)
 		GetUserByToken(context.Context, string) (*User, error)
-		CreateUser(context.Context, *dto.UserInputDTO) (*dto.UserOutputDTO, error)
-		UpdateUser(context.Context, *User, *dto.UserInputDTO) (*dto.UserOutputDTO, error)
+		CreateUser(context.Context, *Profile, *dto.UserInputDTO) (*dto.UserOutputDTO, error)
+		UpdateUser(context.Context, *Profile, *User, *dto.UserInputDTO) (*dto.UserOutputDTO, error)
 		DeleteUser(context.Context, *User) error
 		ResetUserPassword(context.Context, *User) error
 		SetUserPassword(context.Context, *User, *dto.PasswordInputDTO) error
+		UnlockUser(context.Context, *User) error
+		RegisterUser(context.Context, *dto.RegisterInputDTO, string) error
+		VerifyUser(context.Context, *dto.VerifyInputDTO) error
+		ApproveUser(context.Context, *User) (*dto.UserOutputDTO, error)
+		GenerateUserOutputDTO(*User, *filter.Fieldset) *dto.UserOutputDTO
 	}
 )
 
@@ -63,13 +105,14 @@ func (u *User) TableName() string {
 
 func (u *User) ToMap() *map[string]interface{} {
 	mapped := &map[string]interface{}{
-		"name":       u.Name,
-		"mail":       u.Email,
-		"status":     u.Status,
-		"profile_id": u.ProfileID,
-		"new":        u.New,
-		"token":      nil,
-		"password":   nil,
+		"name":           u.Name,
+		"mail":           u.Email,
+		"status":         u.Status,
+		"profile_id":     u.ProfileID,
+		"new":            u.New,
+		"verify_pending": u.VerifyPending,
+		"token":          nil,
+		"password":       nil,
 	}
 
 	if u.Password != nil {
@@ -97,34 +140,55 @@ func (u *User) Bind(userDTO *dto.UserInputDTO) error {
 	return validator.StructValidator.Validate(u)
 }
 
+// ValidatePassword Reports whether the password matches either an argon2id or a bcrypt hash,
+// users signing in only through OIDC have none.
 func (u *User) ValidatePassword(password string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(*u.Password), []byte(password)) == nil
+	return u.Password != nil && hasher.Verify(*u.Password, password)
 }
 
-func (u *User) GenerateToken(expire, originalKey, ip string) (string, error) {
-	decodedKey, err := base64.StdEncoding.DecodeString(originalKey)
-	if err != nil {
-		return "", fmt.Errorf("could not decode key: %v", err.Error())
-	}
+// PasswordNeedsRehash Reports whether the password hash was made by another algorithm or with other parameters.
+func (u *User) PasswordNeedsRehash() bool {
+	return u.Password != nil && hasher.NeedsRehash(*u.Password)
+}
 
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(decodedKey)
-	if err != nil {
-		return "", fmt.Errorf("could not parse key: %v", err.Error())
-	}
+// TwoFactorRequired Reports whether the login must be completed with a second factor.
+func (u *User) TwoFactorRequired() bool {
+	return u.TotpEnabled || (u.Profile != nil && u.Profile.TwoFactor)
+}
 
-	now := time.Now()
-	claims := jwt.MapClaims{
-		"token": u.Token,
-		"ip":    ip,
-		"iat":   now.Unix(),
-	}
+// GenerateTwoFactorToken Generates the short-lived token that only allows completing the login with a second factor.
+func (u *User) GenerateTwoFactorToken(signer TokenSigner, ip string) (string, error) {
+	claims := NewClaims(u, TokenTwoFactor, ip, newTokenID())
+	claims.ExpiresAt = jwt.NewNumericDate(claims.IssuedAt.Add(TwoFactorTokenLife))
+	claims.Persistent = !u.Expire
+
+	return signer.Sign(claims)
+}
+
+// CanImpersonate Reports whether the admin may act as the enabled user, only when its own profile
+// grants every action granted to the user so the impersonation never raises its rights.
+func (u *User) CanImpersonate(user *User) bool {
+	return u.Id != user.Id && user.Status && u.Profile != nil && user.Profile != nil && u.Profile.Covers(user.Profile)
+}
+
+// GenerateImpersonationToken Generates the access token of the impersonation session, carrying the admin in the 'act' claim.
+func (u *User) GenerateImpersonationToken(signer TokenSigner, session *Session, ip string) (string, error) {
+	claims := NewClaims(u, TokenAccess, ip, newTokenID())
+	claims.ExpiresAt = jwt.NewNumericDate(*session.ExpiresAt)
+	claims.Session = session.Token
+	claims.Actor = &Actor{Subject: strconv.FormatUint(uint64(*session.ImpersonatorID), 10)}
+
+	return signer.Sign(claims)
+}
 
-	life, err := helpers.DurationFromString(expire, time.Minute)
-	if err == nil {
-		claims["exp"] = now.Add(life).Unix()
+// GenerateToken Generates a token of the session signed by the signer, with the 'kid' of its key. It
+// expires after its life, only the refresh tokens of the sessions that never expire are issued without one.
+func (u *User) GenerateToken(signer TokenSigner, tokenType string, life time.Duration, ip, sessionToken, tokenID string) (string, error) {
+	claims := NewClaims(u, tokenType, ip, tokenID)
+	claims.Session = sessionToken
+	if life > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(claims.IssuedAt.Add(life))
 	}
-	claims["expire"] = err == nil
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(key)
+	return signer.Sign(claims)
 }
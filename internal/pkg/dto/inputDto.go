@@ -1,18 +1,25 @@
 package dto
 
+import "time"
+
 type (
 	ProductInputDTO struct {
 		Name *string `json:"name" example:"Product 01"`
 	}
 
-	PermissionsInputDTO struct {
-		UserModule    *bool `json:"user_module" example:"true"`
-		ProfileModule *bool `json:"profile_module" example:"true"`
-		ProductModule *bool `json:"product_module" example:"true"`
+	GrantInputDTO struct {
+		Read   *bool `json:"read" example:"true"`
+		Create *bool `json:"create" example:"true"`
+		Update *bool `json:"update" example:"true"`
+		Delete *bool `json:"delete" example:"false"`
 	}
 
+	// PermissionsInputDTO Grants indexed by module name ('user', 'profile', 'product', 'apikey', 'audit').
+	PermissionsInputDTO map[string]GrantInputDTO
+
 	ProfileInputDTO struct {
 		Name        *string             `json:"name" example:"ADMIN"`
+		TwoFactor   *bool               `json:"two_factor" example:"true"`
 		Permissions PermissionsInputDTO `json:"permissions"`
 	}
 
@@ -24,8 +31,40 @@ type (
 	}
 
 	PasswordInputDTO struct {
-		Password        *string `json:"password" example:"secret"`
-		PasswordConfirm *string `json:"password_confirm" example:"secret"`
+		Token           *string `json:"token,omitempty" example:"Qm9hcmQgb2YgZGlyZWN0b3JzIGFwcHJvdmVk"`
+		Password        *string `json:"password" example:"river7stone"`
+		PasswordConfirm *string `json:"password_confirm" example:"river7stone"`
+	}
+
+	RegisterInputDTO struct {
+		Name            *string `json:"name" example:"Amina Wanjiru"`
+		Email           *string `json:"email" example:"amina.wanjiru@email.com"`
+		Password        *string `json:"password" example:"river7stone"`
+		PasswordConfirm *string `json:"password_confirm" example:"river7stone"`
+	}
+
+	ChangePasswordInputDTO struct {
+		CurrentPassword *string `json:"current_password" example:"river7stone"`
+		Password        *string `json:"password" example:"lake9pebble"`
+		PasswordConfirm *string `json:"password_confirm" example:"lake9pebble"`
+	}
+
+	VerifyInputDTO struct {
+		Email string `json:"email" example:"amina.wanjiru@email.com"`
+		Token string `json:"token" example:"Qm9hcmQgb2YgZGlyZWN0b3JzIGFwcHJvdmVk"`
+	}
+
+	ApiKeyInputDTO struct {
+		Name      *string    `json:"name" example:"River gauge 01"`
+		ProfileID *uint      `json:"profile_id" example:"1"`
+		Scopes    []string   `json:"scopes" example:"product:read,product:create"`
+		Status    *bool      `json:"status" example:"true"`
+		ExpiresAt *time.Time `json:"expires_at" example:"2027-01-01T00:00:00Z"`
+	}
+
+	TwoFactorInputDTO struct {
+		Code         string `json:"code" example:"123456"`
+		RecoveryCode string `json:"recovery_code" example:"a1b2c-3d4e5"`
 	}
 
 	AuthInputDTO struct {
@@ -35,14 +74,29 @@ type (
 	}
 )
 
+// IsValid Checks the confirmation, the strength is checked by the password policy.
 func (p PasswordInputDTO) IsValid() bool {
 	if p.Password == nil || p.PasswordConfirm == nil {
 		return false
 	}
 
-	if len(*p.Password) < 5 || len(*p.PasswordConfirm) < 5 {
+	return *p.Password == *p.PasswordConfirm
+}
+
+// IsValid Checks the required fields and the confirmation, the strength is checked by the password policy.
+func (p RegisterInputDTO) IsValid() bool {
+	if p.Name == nil || p.Email == nil {
 		return false
 	}
 
-	return *p.Password == *p.PasswordConfirm
+	return PasswordInputDTO{Password: p.Password, PasswordConfirm: p.PasswordConfirm}.IsValid()
+}
+
+// IsValid Checks the current password was sent and the confirmation of the new one.
+func (p ChangePasswordInputDTO) IsValid() bool {
+	if p.CurrentPassword == nil {
+		return false
+	}
+
+	return PasswordInputDTO{Password: p.Password, PasswordConfirm: p.PasswordConfirm}.IsValid()
 }
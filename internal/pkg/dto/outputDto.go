@@ -1,39 +1,116 @@
 package dto
 
+import "time"
+
 type (
 	ListItemsOutputDTO struct {
-		Items interface{} `json:"items"`
-		Count int64       `json:"count"`
+		Items      interface{} `json:"items"`
+		Count      *int64      `json:"count,omitempty"`
+		NextCursor string      `json:"next_cursor,omitempty"`
+		PrevCursor string      `json:"prev_cursor,omitempty"`
+	}
+
+	// SearchOutputDTO Matches of the global search by module, only the modules readable by the caller
+	// are searched.
+	SearchOutputDTO struct {
+		Users    *ListItemsOutputDTO `json:"users,omitempty"`
+		Profiles *ListItemsOutputDTO `json:"profiles,omitempty"`
+		Products *ListItemsOutputDTO `json:"products,omitempty"`
 	}
 
-	PermissionsOutputDTO struct {
-		UserModule    bool `json:"user_module" example:"true"`
-		ProfileModule bool `json:"profile_module" example:"true"`
-		ProductModule bool `json:"product_module" example:"true"`
+	GrantOutputDTO struct {
+		Read   bool `json:"read" example:"true"`
+		Create bool `json:"create" example:"true"`
+		Update bool `json:"update" example:"true"`
+		Delete bool `json:"delete" example:"false"`
 	}
 
+	// PermissionsOutputDTO Grants indexed by module name ('user', 'profile', 'product', 'apikey', 'audit').
+	PermissionsOutputDTO map[string]GrantOutputDTO
+
 	ProfileOutputDTO struct {
-		Id          uint                  `json:"id" example:"1"`
-		Name        string                `json:"name" example:"ADMIN"`
-		Permissions *PermissionsOutputDTO `json:"permissions,omitempty"`
+		Id          uint                 `json:"id" example:"1"`
+		Name        string               `json:"name,omitempty" example:"ADMIN"`
+		TwoFactor   *bool                `json:"two_factor,omitempty" example:"false"`
+		Permissions PermissionsOutputDTO `json:"permissions,omitempty"`
+		Headline    string               `json:"headline,omitempty" example:"<mark>ADMIN</mark>"`
 	}
 
 	ProductOutputDTO struct {
-		Id   uint   `json:"id" example:"1"`
-		Name string `json:"name" example:"Product 01"`
+		Id       uint   `json:"id" example:"1"`
+		Name     string `json:"name" example:"Product 01"`
+		Headline string `json:"headline,omitempty" example:"<mark>Product</mark> 01"`
 	}
 
 	UserOutputDTO struct {
-		Id      uint             `json:"id" example:"1"`
-		Name    string           `json:"name" example:"John Cena"`
-		Email   string           `json:"email" example:"john.cena@email.com"`
-		Status  bool             `json:"status" example:"true"`
-		Profile ProfileOutputDTO `json:"profile"`
+		Id             uint             `json:"id" example:"1"`
+		Name           string           `json:"name" example:"John Cena"`
+		Email          string           `json:"email" example:"john.cena@email.com"`
+		Status         bool             `json:"status" example:"true"`
+		Verified       bool             `json:"verified" example:"true"`
+		Profile        ProfileOutputDTO `json:"profile"`
+		ImpersonatorID *uint            `json:"impersonator_id,omitempty" example:"1"`
+		Headline       string           `json:"headline,omitempty" example:"<mark>John</mark> Cena john.cena@email.com"`
+	}
+
+	ApiKeyOutputDTO struct {
+		Id        uint             `json:"id" example:"1"`
+		Name      string           `json:"name" example:"River gauge 01"`
+		Prefix    string           `json:"prefix" example:"msd_Xk3vQ9aB"`
+		Key       string           `json:"key,omitempty" example:"msd_Xk3vQ9aBzT0pL8mN2wR5yC7eH1jK4uV6sD9fG3hJ0qW"`
+		Profile   ProfileOutputDTO `json:"profile"`
+		Scopes    []string         `json:"scopes" example:"product:read,product:create"`
+		Status    bool             `json:"status" example:"true"`
+		ExpiresAt *time.Time       `json:"expires_at" example:"2027-01-01T00:00:00Z"`
+		LastUsed  *time.Time       `json:"last_used" example:"2026-03-01T09:30:00Z"`
+		CreatedAt time.Time        `json:"created_at" example:"2026-03-01T08:00:00Z"`
+	}
+
+	AuditChangeOutputDTO struct {
+		Before interface{} `json:"before" swaggertype:"string" example:"Volunteer"`
+		After  interface{} `json:"after" swaggertype:"string" example:"Coordinator"`
+	}
+
+	AuditLogOutputDTO struct {
+		Id             uint                            `json:"id" example:"1"`
+		ActorID        *uint                           `json:"actor_id" example:"1"`
+		ApiKeyID       *uint                           `json:"api_key_id" example:"1"`
+		ImpersonatedID *uint                           `json:"impersonated_id" example:"2"`
+		IP             string                          `json:"ip" example:"127.0.0.1"`
+		RequestID      string                          `json:"request_id" example:"3f2b9c1e-8d4a-4e5f-9b6c-7a1d2e3f4a5b"`
+		Action         string                          `json:"action" example:"update"`
+		Entity         string                          `json:"entity" example:"profiles"`
+		EntityID       *uint                           `json:"entity_id" example:"2"`
+		Diff           map[string]AuditChangeOutputDTO `json:"diff"`
+		CreatedAt      time.Time                       `json:"created_at" example:"2024-03-01T08:00:00Z"`
+	}
+
+	SessionOutputDTO struct {
+		Id             uint      `json:"id" example:"1"`
+		IP             string    `json:"ip" example:"127.0.0.1"`
+		Agent          string    `json:"agent" example:"Mozilla/5.0 (Linux; Android 13)"`
+		CreatedAt      time.Time `json:"created_at" example:"2024-03-01T08:00:00Z"`
+		LastSeen       time.Time `json:"last_seen" example:"2024-03-01T09:30:00Z"`
+		Current        bool      `json:"current" example:"true"`
+		ImpersonatorID *uint     `json:"impersonator_id,omitempty" example:"1"`
+	}
+
+	TwoFactorSetupOutputDTO struct {
+		Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+		URI    string `json:"uri" example:"otpauth://totp/MSAADA:john.cena@email.com?secret=JBSWY3DPEHPK3PXP&issuer=MSAADA"`
+	}
+
+	RecoveryCodesOutputDTO struct {
+		RecoveryCodes []string `json:"recovery_codes" example:"a1b2c-3d4e5,f6g7h-i8j9k"`
 	}
 
 	AuthOutputDTO struct {
-		User         *UserOutputDTO `json:"user,omitempty"`
-		AccessToken  string         `json:"accesstoken"`
-		RefreshToken string         `json:"refreshtoken"`
+		User           *UserOutputDTO `json:"user,omitempty"`
+		AccessToken    string         `json:"accesstoken,omitempty"`
+		RefreshToken   string         `json:"refreshtoken,omitempty"`
+		TwoFactorToken string         `json:"twofactortoken,omitempty"`
+		TwoFactorSetup bool           `json:"twofactorsetup,omitempty"`
+		RecoveryCodes  []string       `json:"recovery_codes,omitempty"`
+		CsrfToken      string         `json:"csrftoken,omitempty"`
 	}
 )
@@ -15,30 +15,69 @@ func NewTranslation(localizer *goi18n.Localizer) *Translation {
 }
 
 type Translation struct {
-	ErrGeneric              error
-	ErrInvalidId            error
-	ErrInvalidDatas         error
-	ErrManyRequest          error
-	ErrorNonexistentRoute   error
-	ErrUndefinedColumn      error
-	ErrExpiredToken         error
-	ErrDisabledUser         error
-	ErrIncorrectPassword    error
-	ErrPassUnmatch          error
-	ErrUserHasPass          error
-	ErrInvalidIpAssociation error
+	ErrGeneric                error
+	ErrInvalidId              error
+	ErrInvalidDatas           error
+	ErrManyRequest            error
+	ErrorNonexistentRoute     error
+	ErrUndefinedColumn        error
+	ErrInvalidSort            error
+	ErrInvalidFilter          error
+	ErrInvalidCursor          error
+	ErrInvalidFieldset        error
+	ErrSearchRequired         error
+	ErrExpiredToken           error
+	ErrDisabledUser           error
+	ErrIncorrectPassword      error
+	ErrPassUnmatch            error
+	ErrUserHasPass            error
+	ErrInvalidIpAssociation   error
+	ErrWithoutPermission      error
+	ErrInvalidSession         error
+	ErrInvalidTokenType       error
+	ErrInvalidApiKey          error
+	ErrImpersonationDenied    error
+	ErrImpersonationForbidden error
+	ErrInvalidCsrfToken       error
+	ErrTokenReused            error
+	ErrSessionNotFound        error
+	ErrInvalidPasswordToken   error
+	ErrTwoFactorEnabled       error
+	ErrTwoFactorRequired      error
+	ErrInvalidTwoFactorCode   error
+	ErrLoginLocked            error
+	ErrRegisterLocked         error
+	ErrOidcDisabled           error
+	ErrOidcLogin              error
+	ErrOidcEmailNotVerified   error
+	ErrPasswordTooShort       error
+	ErrPasswordTooLong        error
+	ErrPasswordNoUpper        error
+	ErrPasswordNoLower        error
+	ErrPasswordNoDigit        error
+	ErrPasswordNoSymbol       error
+	ErrPasswordPersonal       error
+	ErrPasswordCommon         error
+	ErrPasswordReused         error
 
 	ErrProductUsed       error
 	ErrProductNotFound   error
 	ErrProductRegistered error
 
+	ErrApiKeyNotFound  error
+	ErrInvalidScope    error
+	ErrApiKeyForbidden error
+
 	ErrProfileUsed       error
 	ErrProfileNotFound   error
 	ErrProfileRegistered error
+	ErrInvalidModule     error
+	ErrGrantForbidden    error
 
-	ErrUserUsed       error
-	ErrUserNotFound   error
-	ErrUserRegistered error
+	ErrUserUsed        error
+	ErrUserNotFound    error
+	ErrUserRegistered  error
+	ErrUserNotVerified error
 }
 
 func (s *Translation) loadTranslations(localizer *goi18n.Localizer) {
@@ -48,22 +87,60 @@ func (s *Translation) loadTranslations(localizer *goi18n.Localizer) {
 	s.ErrManyRequest = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrManyRequest"}, PluralCount: 1}))
 	s.ErrorNonexistentRoute = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrorNonexistentRoute"}, PluralCount: 1}))
 	s.ErrUndefinedColumn = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrUndefinedColumn"}, PluralCount: 1}))
+	s.ErrInvalidSort = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidSort"}, PluralCount: 1}))
+	s.ErrInvalidFilter = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidFilter"}, PluralCount: 1}))
+	s.ErrInvalidCursor = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidCursor"}, PluralCount: 1}))
+	s.ErrInvalidFieldset = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidFieldset"}, PluralCount: 1}))
+	s.ErrSearchRequired = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrSearchRequired"}, PluralCount: 1}))
 	s.ErrExpiredToken = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrExpiredToken"}, PluralCount: 1}))
 	s.ErrDisabledUser = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrDisabledUser"}, PluralCount: 1}))
 	s.ErrIncorrectPassword = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrIncorrectPassword"}, PluralCount: 1}))
 	s.ErrPassUnmatch = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPassUnmatch"}, PluralCount: 1}))
 	s.ErrUserHasPass = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrUserHasPass"}, PluralCount: 1}))
 	s.ErrInvalidIpAssociation = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidIpAssociation"}, PluralCount: 1}))
+	s.ErrWithoutPermission = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrWithoutPermission"}, PluralCount: 1}))
+	s.ErrInvalidSession = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidSession"}, PluralCount: 1}))
+	s.ErrInvalidTokenType = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidTokenType"}, PluralCount: 1}))
+	s.ErrInvalidApiKey = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidApiKey"}, PluralCount: 1}))
+	s.ErrImpersonationDenied = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrImpersonationDenied"}, PluralCount: 1}))
+	s.ErrImpersonationForbidden = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrImpersonationForbidden"}, PluralCount: 1}))
+	s.ErrInvalidCsrfToken = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidCsrfToken"}, PluralCount: 1}))
+	s.ErrTokenReused = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrTokenReused"}, PluralCount: 1}))
+	s.ErrSessionNotFound = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrSessionNotFound"}, PluralCount: 1}))
+	s.ErrInvalidPasswordToken = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidPasswordToken"}, PluralCount: 1}))
+	s.ErrTwoFactorEnabled = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrTwoFactorEnabled"}, PluralCount: 1}))
+	s.ErrTwoFactorRequired = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrTwoFactorRequired"}, PluralCount: 1}))
+	s.ErrInvalidTwoFactorCode = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidTwoFactorCode"}, PluralCount: 1}))
+	s.ErrLoginLocked = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrLoginLocked"}, PluralCount: 1}))
+	s.ErrRegisterLocked = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrRegisterLocked"}, PluralCount: 1}))
+	s.ErrOidcDisabled = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrOidcDisabled"}, PluralCount: 1}))
+	s.ErrOidcLogin = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrOidcLogin"}, PluralCount: 1}))
+	s.ErrOidcEmailNotVerified = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrOidcEmailNotVerified"}, PluralCount: 1}))
+	s.ErrPasswordTooShort = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordTooShort"}, PluralCount: 1}))
+	s.ErrPasswordTooLong = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordTooLong"}, PluralCount: 1}))
+	s.ErrPasswordNoUpper = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordNoUpper"}, PluralCount: 1}))
+	s.ErrPasswordNoLower = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordNoLower"}, PluralCount: 1}))
+	s.ErrPasswordNoDigit = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordNoDigit"}, PluralCount: 1}))
+	s.ErrPasswordNoSymbol = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordNoSymbol"}, PluralCount: 1}))
+	s.ErrPasswordPersonal = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordPersonal"}, PluralCount: 1}))
+	s.ErrPasswordCommon = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordCommon"}, PluralCount: 1}))
+	s.ErrPasswordReused = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrPasswordReused"}, PluralCount: 1}))
 
 	s.ErrProductUsed = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrProductUsed"}, PluralCount: 1}))
 	s.ErrProductNotFound = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrProductNotFound"}, PluralCount: 1}))
 	s.ErrProductRegistered = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrProductRegistered"}, PluralCount: 1}))
+	s.ErrApiKeyNotFound = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrApiKeyNotFound"}, PluralCount: 1}))
+	s.ErrInvalidScope = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidScope"}, PluralCount: 1}))
+	s.ErrApiKeyForbidden = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrApiKeyForbidden"}, PluralCount: 1}))
 
 	s.ErrProfileUsed = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrProfileUsed"}, PluralCount: 1}))
 	s.ErrProfileNotFound = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrProfileNotFound"}, PluralCount: 1}))
 	s.ErrProfileRegistered = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrProfileRegistered"}, PluralCount: 1}))
+	s.ErrInvalidModule = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrInvalidModule"}, PluralCount: 1}))
+	s.ErrGrantForbidden = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrGrantForbidden"}, PluralCount: 1}))
 
 	s.ErrUserUsed = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrUserUsed"}, PluralCount: 1}))
 	s.ErrUserNotFound = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrUserNotFound"}, PluralCount: 1}))
 	s.ErrUserRegistered = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrUserRegistered"}, PluralCount: 1}))
+	s.ErrUserNotVerified = errors.New(localizer.MustLocalize(&goi18n.LocalizeConfig{DefaultMessage: &goi18n.Message{ID: "ErrUserNotVerified"}, PluralCount: 1}))
 }
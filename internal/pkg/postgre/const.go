@@ -1,8 +1,11 @@
 package postgre
 
 const (
-	Profile     string = "Profile"
-	Permissions string = "Permissions"
+	User         string = "User"
+	Profile      string = "Profile"
+	Permissions  string = "Permissions"
+	Impersonator string = "Impersonator"
 
-	ProfilePermission = Profile + "." + Permissions
+	ProfilePermission     = Profile + "." + Permissions
+	UserProfilePermission = User + "." + ProfilePermission
 )
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+)
+
+func NewApiKeyRepository(db *gorm.DB) domain.ApiKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+// apiKeySearchable Columns the list is searched by.
+var apiKeySearchable = filter.Searchable{
+	Table:   domain.ApiKeyTableName,
+	Columns: []string{"name", "prefix"},
+}
+
+// apiKeySortable Fields the list can be sorted by.
+var apiKeySortable = filter.Sortable{
+	"id":         "id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"name":       "name",
+	"prefix":     "prefix",
+	"status":     "status",
+	"expires_at": "expires_at",
+	"last_used":  "last_used",
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func (s *apiKeyRepository) applyFilter(ctx context.Context, filter *filter.Filter) *gorm.DB {
+	db := s.db.WithContext(ctx)
+	db = filter.ApplyConditions(db)
+	db = filter.ApplySearch(db, apiKeySearchable)
+
+	return filter.ApplyOrder(db, apiKeySortable)
+}
+
+func (s *apiKeyRepository) CountApiKeys(ctx context.Context, filter *filter.Filter) (int64, error) {
+	var count int64
+	db := s.applyFilter(ctx, filter)
+	return count, db.Model(&domain.ApiKey{}).Count(&count).Error
+}
+
+func (s *apiKeyRepository) GetApiKeys(ctx context.Context, filter *filter.Filter) (*[]domain.ApiKey, error) {
+	db := filter.ApplyPreload(s.applyFilter(ctx, filter), domain.ApiKeyRelations)
+
+	apiKeys := &[]domain.ApiKey{}
+	return apiKeys, filter.Find(db, apiKeys)
+}
+
+func (s *apiKeyRepository) GetApiKeyByID(ctx context.Context, apiKeyID uint) (*domain.ApiKey, error) {
+	apiKey := &domain.ApiKey{}
+	return apiKey, s.db.WithContext(ctx).Preload(postgre.Profile).First(apiKey, apiKeyID).Error
+}
+
+func (s *apiKeyRepository) GetApiKeyByHash(ctx context.Context, hash string) (*domain.ApiKey, error) {
+	apiKey := &domain.ApiKey{}
+	return apiKey, s.db.WithContext(ctx).Preload(postgre.ProfilePermission).Where("hash = ?", hash).First(apiKey).Error
+}
+
+func (s *apiKeyRepository) CreateApiKey(ctx context.Context, data *dto.ApiKeyInputDTO) (*domain.ApiKey, string, error) {
+	apiKey := &domain.ApiKey{Status: true}
+	if err := apiKey.Bind(data); err != nil {
+		return nil, "", err
+	}
+
+	raw, err := apiKey.GenerateApiKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, raw, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(apiKey).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditCreate, domain.ApiKeyTableName, apiKey.Id, nil, apiKey.ToMap())
+	})
+}
+
+func (s *apiKeyRepository) UpdateApiKey(ctx context.Context, apiKey *domain.ApiKey, data *dto.ApiKeyInputDTO) error {
+	before := apiKey.ToMap()
+	if err := apiKey.Bind(data); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(apiKey).Updates(apiKey.ToMap()).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditUpdate, domain.ApiKeyTableName, apiKey.Id, before, apiKey.ToMap())
+	})
+}
+
+func (s *apiKeyRepository) DeleteApiKey(ctx context.Context, apiKey *domain.ApiKey) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(apiKey).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditDelete, domain.ApiKeyTableName, apiKey.Id, apiKey.ToMap(), nil)
+	})
+}
+
+func (s *apiKeyRepository) TouchApiKey(ctx context.Context, apiKey *domain.ApiKey) error {
+	return s.db.WithContext(ctx).Model(apiKey).UpdateColumn("last_used", apiKey.LastUsed).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+)
+
+// registerAttempt Increments the counter of the key atomically, starting over when the last
+// attempt, or the end of its lock, is older than the window.
+const registerAttempt = `
+INSERT INTO attempts ("key", "count", updated_at) VALUES (@key, 1, NOW())
+ON CONFLICT ("key") DO UPDATE SET
+	"count" = CASE
+		WHEN GREATEST(attempts.updated_at, COALESCE(attempts.locked_until, attempts.updated_at)) < NOW() - make_interval(secs => @window)
+		THEN 1 ELSE attempts."count" + 1 END,
+	updated_at = NOW()
+RETURNING *`
+
+func NewAttemptRepository(db *gorm.DB) domain.AttemptRepository {
+	return &attemptRepository{
+		db: db,
+	}
+}
+
+type attemptRepository struct {
+	db *gorm.DB
+}
+
+func (s *attemptRepository) GetAttempt(ctx context.Context, key string) (*domain.Attempt, error) {
+	attempt := &domain.Attempt{}
+	return attempt, s.db.WithContext(ctx).Where(&domain.Attempt{Key: key}).Limit(1).Find(attempt).Error
+}
+
+func (s *attemptRepository) RegisterAttempt(ctx context.Context, key string, policy *domain.AttemptPolicy) (*domain.Attempt, error) {
+	attempt := &domain.Attempt{}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		params := map[string]interface{}{"key": key, "window": policy.Window.Seconds()}
+		if err := tx.Raw(registerAttempt, params).Scan(attempt).Error; err != nil {
+			return err
+		}
+
+		lock := policy.LockFor(attempt.Count)
+		if lock == 0 {
+			return nil
+		}
+
+		lockedUntil := time.Now().Add(lock)
+		attempt.LockedUntil = &lockedUntil
+		return tx.Model(attempt).Update("locked_until", lockedUntil).Error
+	})
+
+	return attempt, err
+}
+
+func (s *attemptRepository) ResetAttempts(ctx context.Context, keys ...string) error {
+	return s.db.WithContext(ctx).Where("key IN ?", keys).Delete(&domain.Attempt{}).Error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+)
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// auditSearchable Columns the list is searched by.
+var auditSearchable = filter.Searchable{
+	Table:   domain.AuditLogTableName,
+	Columns: []string{"ip", "diff::text"},
+}
+
+// auditSortable Fields the list can be sorted by.
+var auditSortable = filter.Sortable{
+	"id":         "id",
+	"created_at": "created_at",
+	"actor_id":   "actor_id",
+	"action":     "action",
+	"entity":     "entity",
+	"entity_id":  "entity_id",
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// audit Records the mutation in its own transaction, so that neither is kept without the other.
+func audit(tx *gorm.DB, action, entity string, entityID uint, before, after map[string]interface{}) error {
+	return tx.Create(domain.NewAuditLog(tx.Statement.Context, action, entity, entityID, before, after)).Error
+}
+
+func (s *auditRepository) applyFilter(ctx context.Context, filter *filter.Filter) *gorm.DB {
+	db := filter.ApplyConditions(s.db.WithContext(ctx))
+	db = filter.ApplySearch(db, auditSearchable)
+
+	return filter.ApplyOrder(db, auditSortable)
+}
+
+func (s *auditRepository) CountAuditLogs(ctx context.Context, filter *filter.Filter) (int64, error) {
+	var count int64
+	db := s.applyFilter(ctx, filter)
+	return count, db.Model(&domain.AuditLog{}).Count(&count).Error
+}
+
+func (s *auditRepository) GetAuditLogs(ctx context.Context, filter *filter.Filter) (*[]domain.AuditLog, error) {
+	db := s.applyFilter(ctx, filter)
+
+	logs := &[]domain.AuditLog{}
+	return logs, filter.Find(db, logs)
+}
+
+func (s *auditRepository) CreateAuditLog(ctx context.Context, log *domain.AuditLog) error {
+	return s.db.WithContext(ctx).Create(log).Error
+}
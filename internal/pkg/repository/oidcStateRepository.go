@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+)
+
+func NewOidcStateRepository(db *gorm.DB) domain.OidcStateRepository {
+	return &oidcStateRepository{
+		db: db,
+	}
+}
+
+type oidcStateRepository struct {
+	db *gorm.DB
+}
+
+// CreateOidcState Stores the state, dropping the expired ones left by abandoned logins.
+func (s *oidcStateRepository) CreateOidcState(ctx context.Context, state *domain.OidcState) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&domain.OidcState{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(state).Error
+	})
+}
+
+// UseOidcState Deletes and returns the unexpired state of the hash, so that it is used only once.
+func (s *oidcStateRepository) UseOidcState(ctx context.Context, hash string) (*domain.OidcState, error) {
+	states := []domain.OidcState{}
+	err := s.db.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("hash = ? AND expires_at > ?", hash, time.Now()).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(states) == 0 {
+		return nil, domain.ErrInvalidOidcState
+	}
+
+	return &states[0], nil
+}
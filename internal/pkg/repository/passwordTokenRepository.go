@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
+)
+
+func NewPasswordTokenRepository(db *gorm.DB) domain.PasswordTokenRepository {
+	return &passwordTokenRepository{
+		db: db,
+	}
+}
+
+type passwordTokenRepository struct {
+	db *gorm.DB
+}
+
+// CreatePasswordToken Stores the token, superseding the pending tokens of the same user and kind.
+func (s *passwordTokenRepository) CreatePasswordToken(ctx context.Context, token *domain.PasswordToken) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PasswordToken{}).
+			Where("user_id = ? AND kind = ? AND used_at IS NULL", token.UserID, token.Kind).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit(postgre.User).Create(token).Error
+	})
+}
+
+// CheckPasswordToken Checks the token is pending, unexpired and of one of the kinds, without consuming it.
+func (s *passwordTokenRepository) CheckPasswordToken(ctx context.Context, userID uint, hash string, kinds ...string) error {
+	var count int64
+	err := pendingToken(s.db.WithContext(ctx), userID, hash, kinds, time.Now()).Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return domain.ErrInvalidPasswordToken
+	}
+
+	return nil
+}
+
+// UsePasswordToken Consumes the token when it is pending, unexpired and of one of the kinds.
+func (s *passwordTokenRepository) UsePasswordToken(ctx context.Context, userID uint, hash string, kinds ...string) error {
+	return usePasswordToken(s.db.WithContext(ctx), userID, hash, kinds...)
+}
+
+// usePasswordToken Consumes the token in the transaction, so it is kept when the change it allows fails.
+func usePasswordToken(tx *gorm.DB, userID uint, hash string, kinds ...string) error {
+	now := time.Now()
+	result := pendingToken(tx, userID, hash, kinds, now).Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidPasswordToken
+	}
+
+	return nil
+}
+
+// pendingToken Selects the token when it is pending, unexpired at the moment and of one of the kinds.
+func pendingToken(db *gorm.DB, userID uint, hash string, kinds []string, now time.Time) *gorm.DB {
+	return db.Model(&domain.PasswordToken{}).
+		Where("user_id = ? AND hash = ? AND kind IN ? AND used_at IS NULL AND expires_at > ?", userID, hash, kinds, now)
+}
@@ -16,15 +16,32 @@ func NewProductRepository(db *gorm.DB) domain.ProductRepository {
 	}
 }
 
+// productSearchable Columns the list is searched by.
+var productSearchable = filter.Searchable{
+	Table:    domain.ProductTableName,
+	Columns:  []string{"name"},
+	Vector:   filter.SearchVectorColumn,
+	Document: filter.SearchDocument("name"),
+}
+
+// productSortable Fields the list can be sorted by.
+var productSortable = filter.Sortable{
+	"id":         "id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"name":       "name",
+}
+
 type productRepository struct {
 	db *gorm.DB
 }
 
 func (s *productRepository) applyFilter(ctx context.Context, filter *filter.Filter) *gorm.DB {
 	db := s.db.WithContext(ctx)
-	db = filter.ApplySearchLike(db, "name")
+	db = filter.ApplyConditions(db)
+	db = filter.ApplySearch(db, productSearchable)
 
-	return filter.ApplyOrder(db)
+	return filter.ApplyOrder(db, productSortable)
 }
 
 func (s *productRepository) CountProducts(ctx context.Context, filter *filter.Filter) (int64, error) {
@@ -34,10 +51,10 @@ func (s *productRepository) CountProducts(ctx context.Context, filter *filter.Fi
 }
 
 func (s *productRepository) GetProducts(ctx context.Context, filter *filter.Filter) (*[]domain.Product, error) {
-	db := filter.ApplyPagination(s.applyFilter(ctx, filter))
+	db := s.applyFilter(ctx, filter)
 
 	products := &[]domain.Product{}
-	return products, db.Find(products).Error
+	return products, filter.Find(db, products)
 }
 
 func (s *productRepository) GetProductByID(ctx context.Context, productID uint) (*domain.Product, error) {
@@ -51,17 +68,36 @@ func (s *productRepository) CreateProduct(ctx context.Context, data *dto.Product
 		return nil, err
 	}
 
-	return product, s.db.WithContext(ctx).Create(product).Error
+	return product, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditCreate, domain.ProductTableName, product.Id, nil, product.ToMap())
+	})
 }
 
 func (s *productRepository) UpdateProduct(ctx context.Context, product *domain.Product, data *dto.ProductInputDTO) error {
+	before := product.ToMap()
 	if err := product.Bind(data); err != nil {
 		return err
 	}
 
-	return s.db.WithContext(ctx).Model(product).Updates(product.ToMap()).Error
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(product).Updates(product.ToMap()).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditUpdate, domain.ProductTableName, product.Id, before, product.ToMap())
+	})
 }
 
 func (s *productRepository) DeleteProduct(ctx context.Context, product *domain.Product) error {
-	return s.db.WithContext(ctx).Delete(product).Error
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(product).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditDelete, domain.ProductTableName, product.Id, product.ToMap(), nil)
+	})
 }
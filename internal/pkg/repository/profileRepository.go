@@ -17,15 +17,32 @@ func NewProfileRepository(db *gorm.DB) domain.ProfileRepository {
 	}
 }
 
+// profileSearchable Columns the list is searched by.
+var profileSearchable = filter.Searchable{
+	Table:    domain.ProfileTableName,
+	Columns:  []string{"name"},
+	Vector:   filter.SearchVectorColumn,
+	Document: filter.SearchDocument("name"),
+}
+
+// profileSortable Fields the list can be sorted by.
+var profileSortable = filter.Sortable{
+	"id":         "id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"name":       "name",
+}
+
 type profileRepository struct {
 	db *gorm.DB
 }
 
 func (s *profileRepository) applyFilter(ctx context.Context, filter *filter.Filter) *gorm.DB {
 	db := s.db.WithContext(ctx)
-	db = filter.ApplySearchLike(db, "name")
+	db = filter.ApplyConditions(db)
+	db = filter.ApplySearch(db, profileSearchable)
 
-	return filter.ApplyOrder(db)
+	return filter.ApplyOrder(db, profileSortable)
 }
 
 func (s *profileRepository) CountProfiles(ctx context.Context, filter *filter.Filter) (int64, error) {
@@ -35,10 +52,10 @@ func (s *profileRepository) CountProfiles(ctx context.Context, filter *filter.Fi
 }
 
 func (s *profileRepository) GetProfiles(ctx context.Context, filter *filter.Filter) (*[]domain.Profile, error) {
-	db := filter.ApplyPagination(s.applyFilter(ctx, filter))
+	db := filter.ApplyPreload(s.applyFilter(ctx, filter), domain.ProfileRelations)
 
 	profiles := &[]domain.Profile{}
-	return profiles, db.Preload(clause.Associations).Find(profiles).Error
+	return profiles, filter.Find(db, profiles)
 }
 
 func (s *profileRepository) GetProfileByID(ctx context.Context, profileID uint) (*domain.Profile, error) {
@@ -46,27 +63,53 @@ func (s *profileRepository) GetProfileByID(ctx context.Context, profileID uint)
 	return profile, s.db.WithContext(ctx).Preload(clause.Associations).First(profile, profileID).Error
 }
 
+func (s *profileRepository) GetProfileByName(ctx context.Context, name string) (*domain.Profile, error) {
+	profile := &domain.Profile{}
+	return profile, s.db.WithContext(ctx).Preload(clause.Associations).Where("name = ?", name).First(profile).Error
+}
+
 func (s *profileRepository) CreateProfile(ctx context.Context, data *dto.ProfileInputDTO) (*domain.Profile, error) {
 	profile := &domain.Profile{}
 	if err := profile.Bind(data); err != nil {
 		return nil, err
 	}
 
-	return profile, s.db.WithContext(ctx).Create(profile).Error
+	return profile, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(profile).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditCreate, domain.ProfileTableName, profile.Id, nil, profile.AuditMap())
+	})
 }
 
 func (s *profileRepository) UpdateProfile(ctx context.Context, profile *domain.Profile, data *dto.ProfileInputDTO) error {
+	before := profile.AuditMap()
 	if err := profile.Bind(data); err != nil {
 		return err
 	}
 
-	if err := s.db.WithContext(ctx).Model(&profile.Permissions).Updates(profile.Permissions.ToMap()).Error; err != nil {
-		return err
-	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range profile.Permissions {
+			if err := tx.Save(&profile.Permissions[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(profile).Updates(profile.ToMap()).Error; err != nil {
+			return err
+		}
 
-	return s.db.WithContext(ctx).Updates(profile).Error
+		return audit(tx, domain.AuditUpdate, domain.ProfileTableName, profile.Id, before, profile.AuditMap())
+	})
 }
 
 func (s *profileRepository) DeleteProfile(ctx context.Context, profile *domain.Profile) error {
-	return s.db.WithContext(ctx).Select(clause.Associations).Delete(profile).Error
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select(clause.Associations).Delete(profile).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditDelete, domain.ProfileTableName, profile.Id, profile.AuditMap(), nil)
+	})
 }
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+)
+
+// hitRateLimit Increments the counter of the key atomically, starting a new window once the last one ended.
+const hitRateLimit = `
+INSERT INTO ` + domain.RateLimitTableName + ` ("key", "count", reset_at) VALUES (@key, 1, NOW() + make_interval(secs => @window))
+ON CONFLICT ("key") DO UPDATE SET
+	"count" = CASE WHEN ` + domain.RateLimitTableName + `.reset_at <= NOW() THEN 1 ELSE ` + domain.RateLimitTableName + `."count" + 1 END,
+	reset_at = CASE WHEN ` + domain.RateLimitTableName + `.reset_at <= NOW() THEN NOW() + make_interval(secs => @window) ELSE ` + domain.RateLimitTableName + `.reset_at END
+RETURNING *`
+
+// rateLimitPurge Minimum time between two deletions of the ended windows.
+const rateLimitPurge = time.Minute
+
+func NewRateLimitRepository(db *gorm.DB) domain.RateLimitRepository {
+	return &rateLimitRepository{
+		db: db,
+	}
+}
+
+type rateLimitRepository struct {
+	db     *gorm.DB
+	mu     sync.Mutex
+	purged time.Time
+}
+
+// Hit Implementation of 'Hit', the ended windows of every key are deleted at most once per minute. A
+// failed deletion is logged and the hit still counted.
+func (s *rateLimitRepository) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	purge := time.Since(s.purged) > rateLimitPurge
+	if purge {
+		s.purged = time.Now()
+	}
+	s.mu.Unlock()
+
+	if purge {
+		if err := s.db.WithContext(ctx).Where("reset_at <= ?", time.Now()).Delete(&domain.RateLimit{}).Error; err != nil {
+			log.Println(err.Error())
+		}
+	}
+
+	limit := &domain.RateLimit{}
+	params := map[string]interface{}{"key": key, "window": window.Seconds()}
+	if err := s.db.WithContext(ctx).Raw(hitRateLimit, params).Scan(limit).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return limit.Count, limit.ResetAt, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
+)
+
+func NewSessionRepository(db *gorm.DB) domain.SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func (s *sessionRepository) GetSessionByToken(ctx context.Context, token string) (*domain.Session, error) {
+	session := &domain.Session{Token: token}
+	return session, s.db.WithContext(ctx).Preload(postgre.UserProfilePermission).Preload(postgre.Impersonator).Where(session).First(session).Error
+}
+
+// GetUserSessions Returns the active sessions of the user, most recently seen first.
+func (s *sessionRepository) GetUserSessions(ctx context.Context, userID uint) (*[]domain.Session, error) {
+	sessions := &[]domain.Session{}
+	return sessions, s.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
+		Order("last_seen desc").
+		Find(sessions).Error
+}
+
+func (s *sessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	return s.db.WithContext(ctx).Omit(postgre.User).Create(session).Error
+}
+
+// RotateSession Replaces the refresh ID, failing with 'ErrTokenReused' when 'refreshID' was already rotated.
+func (s *sessionRepository) RotateSession(ctx context.Context, session *domain.Session, refreshID string) error {
+	result := s.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND refresh_id = ? AND revoked_at IS NULL", session.Id, refreshID).
+		Updates(map[string]interface{}{
+			"refresh_id":     session.RefreshID,
+			"ip":             session.IP,
+			"last_seen":      session.LastSeen,
+			"expires_at":     session.ExpiresAt,
+			"refresh_key_id": session.RefreshKeyID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrTokenReused
+	}
+
+	return nil
+}
+
+func (s *sessionRepository) TouchSession(ctx context.Context, session *domain.Session) error {
+	return s.db.WithContext(ctx).Model(session).UpdateColumns(map[string]interface{}{
+		"ip":        session.IP,
+		"last_seen": session.LastSeen,
+	}).Error
+}
+
+func (s *sessionRepository) RevokeSession(ctx context.Context, session *domain.Session) error {
+	return s.db.WithContext(ctx).Model(session).Where("revoked_at IS NULL").Update("revoked_at", time.Now()).Error
+}
+
+func (s *sessionRepository) RevokeUserSession(ctx context.Context, userID, sessionID uint) error {
+	result := s.db.WithContext(ctx).Model(&domain.Session{}).Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// RevokeUserSessions Revokes the sessions of the user along with the impersonation sessions it opened.
+func (s *sessionRepository) RevokeUserSessions(ctx context.Context, userID uint) error {
+	return s.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("(user_id = ? OR impersonator_id = ?) AND revoked_at IS NULL", userID, userID).
+		Update("revoked_at", time.Now()).Error
+}
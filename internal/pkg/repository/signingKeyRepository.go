@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+)
+
+func NewSigningKeyRepository(db *gorm.DB) domain.SigningKeyRepository {
+	return &signingKeyRepository{
+		db: db,
+	}
+}
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+// referencedKey Condition of the keys that signed the refresh token of an active session, the tokens of
+// the sessions that never expire have no expiration either.
+const referencedKey = "EXISTS (SELECT 1 FROM " + domain.SessionTableName + " WHERE " + domain.SessionTableName + ".refresh_key_id = " +
+	domain.SigningKeyTableName + ".id AND " + domain.SessionTableName + ".revoked_at IS NULL AND (" + domain.SessionTableName +
+	".expires_at IS NULL OR " + domain.SessionTableName + ".expires_at > @now))"
+
+// GetSigningKeys Returns the keys still valid for the token type, the current one first. The retired
+// keys are valid until they expire or, past that, while an active session references them.
+func (s *signingKeyRepository) GetSigningKeys(ctx context.Context, use string) ([]domain.SigningKey, error) {
+	keys := []domain.SigningKey{}
+	return keys, s.db.WithContext(ctx).
+		Where("use = @use AND (current OR expires_at > @now OR "+referencedKey+")", sql.Named("use", use), sql.Named("now", time.Now())).
+		Order("current DESC, created_at DESC").
+		Find(&keys).Error
+}
+
+// ImportSigningKey Stores a key that already exists outside the database, it only becomes the
+// current one when the token type has none.
+func (s *signingKeyRepository) ImportSigningKey(ctx context.Context, key *domain.SigningKey) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.SigningKey{}).Where("use = ? AND current", key.Use).Count(&count).Error; err != nil {
+			return err
+		}
+
+		key.Current = count == 0
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key).Error
+	})
+}
+
+// RotateSigningKey Makes the key the current one, retiring the previous at 'retireAt', and deletes the
+// retired keys no longer valid.
+func (s *signingKeyRepository) RotateSigningKey(ctx context.Context, key *domain.SigningKey, retireAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("use = @use AND NOT current AND expires_at <= @now AND NOT "+referencedKey, sql.Named("use", key.Use), sql.Named("now", time.Now())).
+			Delete(&domain.SigningKey{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.SigningKey{}).Where("use = ? AND current", key.Use).Updates(map[string]interface{}{
+			"current":    false,
+			"expires_at": retireAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		key.Current = true
+		return tx.Create(key).Error
+	})
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
+	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
+)
+
+func NewTwoFactorRepository(db *gorm.DB) domain.TwoFactorRepository {
+	return &twoFactorRepository{
+		db: db,
+	}
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// SetTwoFactorSecret Stores a pending secret, only enabled after the first valid code.
+func (s *twoFactorRepository) SetTwoFactorSecret(ctx context.Context, user *domain.User, secret string) error {
+	user.TotpSecret = &secret
+	user.TotpEnabled = false
+	user.TotpStep = 0
+
+	return s.db.WithContext(ctx).Model(user).Updates(map[string]interface{}{
+		"totp_secret":  secret,
+		"totp_enabled": false,
+		"totp_step":    0,
+	}).Error
+}
+
+// EnableTwoFactor Enables the pending secret, replacing the previous recovery codes.
+func (s *twoFactorRepository) EnableTwoFactor(ctx context.Context, user *domain.User, codes []domain.RecoveryCode) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.Id).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit(postgre.User).Create(&codes).Error; err != nil {
+			return err
+		}
+
+		user.TotpEnabled = true
+		return tx.Model(user).Update("totp_enabled", true).Error
+	})
+}
+
+func (s *twoFactorRepository) DisableTwoFactor(ctx context.Context, user *domain.User) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.Id).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		user.TotpSecret = nil
+		user.TotpEnabled = false
+		return tx.Model(user).Updates(map[string]interface{}{
+			"totp_secret":  nil,
+			"totp_enabled": false,
+		}).Error
+	})
+}
+
+func (s *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, hash string) error {
+	result := s.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// UseTotpStep Stores the step of the accepted TOTP code, only when it is after the last accepted one, so
+// the same code is never accepted twice, even by concurrent requests.
+func (s *twoFactorRepository) UseTotpStep(ctx context.Context, user *domain.User, step int64) error {
+	result := s.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND totp_step < ?", user.Id, step).
+		Update("totp_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	user.TotpStep = step
+	return nil
+}
+
+// UseTwoFactorToken Marks the two-factor token as exchanged, failing with 'ErrInvalidSession' when it
+// already was. The tokens past their expiration are dropped, they are refused anyway.
+func (s *twoFactorRepository) UseTwoFactorToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&domain.UsedTwoFactor{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.UsedTwoFactor{TokenID: tokenID, ExpiresAt: expiresAt})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrInvalidSession
+		}
+
+		return nil
+	})
+}
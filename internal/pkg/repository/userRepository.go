@@ -5,13 +5,14 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/domain"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/dto"
 	"github.com/Duncan-Kiragu/Msaada-Backend/internal/pkg/postgre"
 	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/filter"
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/hasher"
 )
 
 func NewUserRepository(db *gorm.DB) domain.UserRepository {
@@ -20,6 +21,25 @@ func NewUserRepository(db *gorm.DB) domain.UserRepository {
 	}
 }
 
+// userSearchable Columns the list is searched by.
+var userSearchable = filter.Searchable{
+	Table:    domain.UserTableName,
+	Columns:  []string{domain.UserTableName + ".name", domain.UserTableName + ".mail", domain.ProfileTableName + ".name"},
+	Vector:   domain.UserTableName + "." + filter.SearchVectorColumn,
+	Document: filter.SearchDocument(domain.UserTableName+".name", domain.UserTableName+".mail"),
+}
+
+// userSortable Fields the list can be sorted by.
+var userSortable = filter.Sortable{
+	"id":         domain.UserTableName + ".id",
+	"created_at": domain.UserTableName + ".created_at",
+	"updated_at": domain.UserTableName + ".updated_at",
+	"name":       domain.UserTableName + ".name",
+	"mail":       domain.UserTableName + ".mail",
+	"status":     domain.UserTableName + ".status",
+	"profile":    domain.ProfileTableName + ".name",
+}
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -29,10 +49,13 @@ func (s *userRepository) applyFilter(ctx context.Context, filter *filter.UserFil
 	if filter.ProfileID != 0 {
 		db = db.Where(domain.UserTableName+".profile_id = ?", filter.ProfileID)
 	}
+	// The profile is joined to be searched and sorted by, only the columns of the users are selected.
+	db = db.Clauses(clause.Select{Expression: clause.Expr{SQL: domain.UserTableName + ".*"}})
 	db = db.Joins(fmt.Sprintf("JOIN %v ON %v.id = %v.profile_id", domain.ProfileTableName, domain.ProfileTableName, domain.UserTableName))
-	db = filter.ApplySearchLike(db, domain.UserTableName+".name", domain.UserTableName+".mail", domain.ProfileTableName+".name")
+	db = filter.ApplyConditions(db)
+	db = filter.ApplySearch(db, userSearchable)
 
-	return filter.ApplyOrder(db)
+	return filter.ApplyOrder(db, userSortable)
 }
 
 func (s *userRepository) CountUsers(ctx context.Context, filter *filter.UserFilter) (int64, error) {
@@ -42,10 +65,10 @@ func (s *userRepository) CountUsers(ctx context.Context, filter *filter.UserFilt
 }
 
 func (s *userRepository) GetUsers(ctx context.Context, filter *filter.UserFilter) (*[]domain.User, error) {
-	db := filter.ApplyPagination(s.applyFilter(ctx, filter))
+	db := filter.ApplyPreload(s.applyFilter(ctx, filter), domain.UserRelations)
 
 	users := &[]domain.User{}
-	return users, db.Preload(postgre.ProfilePermission).Find(users).Error
+	return users, filter.Find(db, users)
 }
 
 func (s *userRepository) GetUserByID(ctx context.Context, userID uint) (*domain.User, error) {
@@ -69,40 +92,139 @@ func (s *userRepository) CreateUser(ctx context.Context, data *dto.UserInputDTO)
 		return nil, err
 	}
 
-	return user, s.db.WithContext(ctx).Create(user).Error
+	return user, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditCreate, domain.UserTableName, user.Id, nil, *user.ToMap())
+	})
+}
+
+// updateUser Saves the user, auditing the changes since 'before'.
+func (s *userRepository) updateUser(ctx context.Context, user *domain.User, before map[string]interface{}, extra ...func(*gorm.DB) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(user.ToMap()).Error; err != nil {
+			return err
+		}
+
+		for _, run := range extra {
+			if err := run(tx); err != nil {
+				return err
+			}
+		}
+
+		return audit(tx, domain.AuditUpdate, domain.UserTableName, user.Id, before, *user.ToMap())
+	})
 }
 
 func (s *userRepository) UpdateUser(ctx context.Context, user *domain.User, data *dto.UserInputDTO) error {
+	before := *user.ToMap()
 	if err := user.Bind(data); err != nil {
 		return err
 	}
 
-	return s.db.WithContext(ctx).Model(user).Updates(user.ToMap()).Error
+	return s.updateUser(ctx, user, before)
 }
 
 func (s *userRepository) DeleteUser(ctx context.Context, user *domain.User) error {
-	return s.db.WithContext(ctx).Delete(user).Error
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(user).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditDelete, domain.UserTableName, user.Id, *user.ToMap(), nil)
+	})
 }
 
 func (s *userRepository) ResetUserPassword(ctx context.Context, user *domain.User) error {
+	before := *user.ToMap()
 	user.Password = nil
 	user.Token = nil
 	user.New = true
 
-	return s.UpdateUser(ctx, user, &dto.UserInputDTO{})
+	return s.updateUser(ctx, user, before)
 }
 
+// SetUserPassword Sets the password, consuming in the same transaction the invite or reset token of the
+// input when it has one, so the token is kept when the update fails.
 func (s *userRepository) SetUserPassword(ctx context.Context, user *domain.User, pass *dto.PasswordInputDTO) error {
+	before := *user.ToMap()
 	user.New = false
 	user.Token = new(string)
 	*user.Token = uuid.New().String()
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(*pass.Password), bcrypt.DefaultCost)
+	hash, err := hasher.Hash(*pass.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = &hash
+
+	return s.updateUser(ctx, user, before, func(tx *gorm.DB) error {
+		if pass.Token == nil {
+			return nil
+		}
+		return usePasswordToken(tx, user.Id, domain.HashToken(*pass.Token), domain.TokenInvite, domain.TokenReset)
+	}, func(tx *gorm.DB) error {
+		return tx.Create(&domain.PasswordHistory{UserID: user.Id, Hash: *user.Password}).Error
+	})
+}
+
+// RegisterUser Creates the disabled user of a sign-up, waiting for the verification of its mail.
+func (s *userRepository) RegisterUser(ctx context.Context, data *dto.RegisterInputDTO, profileID uint) (*domain.User, error) {
+	user := &domain.User{Status: false, New: false, VerifyPending: true}
+	if err := user.Bind(&dto.UserInputDTO{Name: data.Name, Email: data.Email, ProfileID: &profileID}); err != nil {
+		return nil, err
+	}
+
+	hash, err := hasher.Hash(*data.Password)
+	if err != nil {
+		return nil, err
+	}
+	user.Token = new(string)
+	*user.Token = uuid.New().String()
+	user.Password = &hash
+
+	return user, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&domain.PasswordHistory{UserID: user.Id, Hash: *user.Password}).Error; err != nil {
+			return err
+		}
+
+		return audit(tx, domain.AuditCreate, domain.UserTableName, user.Id, nil, *user.ToMap())
+	})
+}
+
+// VerifyUser Marks the mail of the user as verified, enabling it unless it waits for an approval.
+func (s *userRepository) VerifyUser(ctx context.Context, user *domain.User, enable bool) error {
+	before := *user.ToMap()
+	user.VerifyPending = false
+	user.Status = enable
+
+	return s.updateUser(ctx, user, before)
+}
+
+// RehashUserPassword Replaces the outdated hash of the password with one of the current hasher, the
+// password itself is unchanged so neither the history nor the audit log record it.
+func (s *userRepository) RehashUserPassword(ctx context.Context, user *domain.User, password string) error {
+	hash, err := hasher.Hash(password)
 	if err != nil {
 		return err
 	}
-	user.Password = new(string)
-	*user.Password = string(hash)
 
-	return s.UpdateUser(ctx, user, &dto.UserInputDTO{})
+	if err := s.db.WithContext(ctx).Model(user).Update("password", hash).Error; err != nil {
+		return err
+	}
+
+	user.Password = &hash
+	return nil
+}
+
+// GetPasswordHistory Returns the last 'limit' passwords set by the user, newest first.
+func (s *userRepository) GetPasswordHistory(ctx context.Context, userID uint, limit int) ([]domain.PasswordHistory, error) {
+	history := []domain.PasswordHistory{}
+	return history, s.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&history).Error
 }
@@ -0,0 +1,253 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFilter The value of a filter field has an unknown operator or does not match the field type.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+const (
+	OpEq      = "eq"
+	OpNe      = "ne"
+	OpIn      = "in"
+	OpGt      = "gt"
+	OpGte     = "gte"
+	OpLt      = "lt"
+	OpLte     = "lte"
+	OpBetween = "between"
+	OpIsNull  = "isnull"
+
+	// maxInValues Most values accepted by the 'in' operator.
+	maxInValues = 100
+
+	dateLayout = "2006-01-02"
+	day        = 24 * time.Hour
+)
+
+const (
+	String FieldType = iota
+	Int
+	Bool
+	Time
+)
+
+var operators = map[FieldType][]string{
+	String: {OpEq, OpNe, OpIn, OpIsNull},
+	Int:    {OpEq, OpNe, OpIn, OpGt, OpGte, OpLt, OpLte, OpBetween, OpIsNull},
+	Bool:   {OpEq, OpNe, OpIsNull},
+	Time:   {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpBetween, OpIsNull},
+}
+
+type (
+	// FieldType Type the values of a filter field are parsed as.
+	FieldType int
+
+	// Field Filter field of a resource, the column is given by the resource and never by the request.
+	Field struct {
+		Column string
+		Type   FieldType
+	}
+
+	// Fields Filter fields of a resource by the name of their query parameter.
+	Fields map[string]Field
+
+	// Condition Parsed filter, a SQL condition over a declared column with its bound values.
+	Condition struct {
+		Query string
+		Args  []interface{}
+	}
+)
+
+// ParseConditions Parses the declared fields present in the query, written as 'operator:value' or just
+// 'value' for 'eq'. A field repeated in the query must match all its conditions. The time values are
+// RFC 3339 timestamps or dates, a date covers the whole day.
+func (s *Filter) ParseConditions(fields Fields, query url.Values) error {
+	for name, field := range fields {
+		for _, value := range query[name] {
+			condition, err := field.parse(value)
+			if err != nil {
+				return fmt.Errorf("%w: %v: %v", ErrInvalidFilter, name, err)
+			}
+
+			s.Conditions = append(s.Conditions, *condition)
+		}
+	}
+
+	return nil
+}
+
+// ApplyConditions Applies the parsed conditions.
+func (s *Filter) ApplyConditions(db *gorm.DB) *gorm.DB {
+	for _, condition := range s.Conditions {
+		db = db.Where(condition.Query, condition.Args...)
+	}
+
+	return db
+}
+
+func (s Field) parse(value string) (*Condition, error) {
+	operator, operand, found := strings.Cut(value, ":")
+	if !found || !isOperator(operator) {
+		operator, operand = OpEq, value
+	}
+
+	if !slices.Contains(operators[s.Type], operator) {
+		return nil, fmt.Errorf("operator '%v' not supported", operator)
+	}
+
+	switch operator {
+	case OpIsNull:
+		null, err := strconv.ParseBool(operand)
+		if err != nil {
+			return nil, err
+		}
+		if null {
+			return &Condition{Query: s.Column + " IS NULL"}, nil
+		}
+		return &Condition{Query: s.Column + " IS NOT NULL"}, nil
+	case OpIn:
+		values := strings.Split(operand, ",")
+		if len(values) > maxInValues {
+			return nil, fmt.Errorf("more than %d values", maxInValues)
+		}
+		args := make([]interface{}, len(values))
+		for i, value := range values {
+			arg, err := s.value(value)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = arg
+		}
+		return &Condition{Query: s.Column + " IN ?", Args: []interface{}{args}}, nil
+	case OpBetween:
+		from, to, found := strings.Cut(operand, ",")
+		if !found {
+			return nil, errors.New("'between' needs two values")
+		}
+		return s.between(from, to)
+	}
+
+	if s.Type == Time {
+		return s.timeCondition(operator, operand)
+	}
+
+	arg, err := s.value(operand)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Condition{Query: s.Column + " " + comparison(operator) + " ?", Args: []interface{}{arg}}, nil
+}
+
+func (s Field) value(value string) (interface{}, error) {
+	switch s.Type {
+	case Int:
+		return strconv.ParseInt(value, 10, 64)
+	case Bool:
+		return strconv.ParseBool(value)
+	case Time:
+		moment, _, err := parseTime(value)
+		return moment, err
+	default:
+		return value, nil
+	}
+}
+
+func (s Field) between(from, to string) (*Condition, error) {
+	if s.Type != Time {
+		low, err := s.value(from)
+		if err != nil {
+			return nil, err
+		}
+		high, err := s.value(to)
+		if err != nil {
+			return nil, err
+		}
+		return &Condition{Query: s.Column + " BETWEEN ? AND ?", Args: []interface{}{low, high}}, nil
+	}
+
+	low, _, err := parseTime(from)
+	if err != nil {
+		return nil, err
+	}
+	high, date, err := parseTime(to)
+	if err != nil {
+		return nil, err
+	}
+	if date {
+		return &Condition{Query: s.Column + " >= ? AND " + s.Column + " < ?", Args: []interface{}{low, high.Add(day)}}, nil
+	}
+
+	return &Condition{Query: s.Column + " BETWEEN ? AND ?", Args: []interface{}{low, high}}, nil
+}
+
+// timeCondition Compares with a timestamp, or with the whole day of a date.
+func (s Field) timeCondition(operator, value string) (*Condition, error) {
+	moment, date, err := parseTime(value)
+	if err != nil {
+		return nil, err
+	}
+	if !date {
+		return &Condition{Query: s.Column + " " + comparison(operator) + " ?", Args: []interface{}{moment}}, nil
+	}
+
+	next := moment.Add(day)
+	switch operator {
+	case OpEq:
+		return &Condition{Query: s.Column + " >= ? AND " + s.Column + " < ?", Args: []interface{}{moment, next}}, nil
+	case OpNe:
+		return &Condition{Query: "(" + s.Column + " < ? OR " + s.Column + " >= ?)", Args: []interface{}{moment, next}}, nil
+	case OpGt:
+		return &Condition{Query: s.Column + " >= ?", Args: []interface{}{next}}, nil
+	case OpLte:
+		return &Condition{Query: s.Column + " < ?", Args: []interface{}{next}}, nil
+	default:
+		return &Condition{Query: s.Column + " " + comparison(operator) + " ?", Args: []interface{}{moment}}, nil
+	}
+}
+
+// parseTime Parses a RFC 3339 timestamp or a date, reporting whether it was a date.
+func parseTime(value string) (time.Time, bool, error) {
+	if moment, err := time.Parse(dateLayout, value); err == nil {
+		return moment, true, nil
+	}
+
+	moment, err := time.Parse(time.RFC3339, value)
+	return moment, false, err
+}
+
+func comparison(operator string) string {
+	switch operator {
+	case OpNe:
+		return "<>"
+	case OpGt:
+		return ">"
+	case OpGte:
+		return ">="
+	case OpLt:
+		return "<"
+	case OpLte:
+		return "<="
+	default:
+		return "="
+	}
+}
+
+func isOperator(operator string) bool {
+	for _, operators := range operators {
+		if slices.Contains(operators, operator) {
+			return true
+		}
+	}
+
+	return false
+}
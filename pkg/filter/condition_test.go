@@ -0,0 +1,85 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fields = Fields{
+	"name":       {Column: "items.name", Type: String},
+	"stock":      {Column: "stock", Type: Int},
+	"status":     {Column: "status", Type: Bool},
+	"created_at": {Column: "created_at", Type: Time},
+}
+
+func parse(t *testing.T, query string) (*Filter, error) {
+	values, err := url.ParseQuery(query)
+	assert.Nil(t, err)
+
+	filter := &Filter{}
+	return filter, filter.ParseConditions(fields, values)
+}
+
+// go test -run TestParseConditions
+func TestParseConditions(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]Condition{
+		"status=true":                              {Query: "status = ?", Args: []interface{}{true}},
+		"status=ne:false":                          {Query: "status <> ?", Args: []interface{}{false}},
+		"name=in:a,b":                              {Query: "items.name IN ?", Args: []interface{}{[]interface{}{"a", "b"}}},
+		"name=a:b":                                 {Query: "items.name = ?", Args: []interface{}{"a:b"}},
+		"name=isnull:true":                         {Query: "items.name IS NULL"},
+		"stock=isnull:false":                       {Query: "stock IS NOT NULL"},
+		"stock=gte:10":                             {Query: "stock >= ?", Args: []interface{}{int64(10)}},
+		"stock=between:1,5":                        {Query: "stock BETWEEN ? AND ?", Args: []interface{}{int64(1), int64(5)}},
+		"created_at=gte:2026-01-01":                {Query: "created_at >= ?", Args: []interface{}{day}},
+		"created_at=lte:2026-01-01":                {Query: "created_at < ?", Args: []interface{}{day.Add(24 * time.Hour)}},
+		"created_at=2026-01-01":                    {Query: "created_at >= ? AND created_at < ?", Args: []interface{}{day, day.Add(24 * time.Hour)}},
+		"created_at=between:2026-01-01,2026-01-31": {Query: "created_at >= ? AND created_at < ?", Args: []interface{}{day, day.AddDate(0, 1, 0)}},
+		"created_at=lt:2026-01-01T10:00:00Z":       {Query: "created_at < ?", Args: []interface{}{day.Add(10 * time.Hour)}},
+	}
+
+	for query, expected := range tests {
+		filter, err := parse(t, query)
+		assert.Nil(t, err, query)
+		assert.Equal(t, []Condition{expected}, filter.Conditions, query)
+	}
+
+	// A repeated field must match all its conditions, the fields not declared are ignored.
+	filter, err := parse(t, "stock=gt:1&stock=lt:5&page=2&other=eq:1")
+	assert.Nil(t, err)
+	assert.Len(t, filter.Conditions, 2)
+}
+
+// go test -run TestParseConditionsInvalid
+func TestParseConditionsInvalid(t *testing.T) {
+	for _, query := range []string{
+		"stock=abc",
+		"stock=between:1",
+		"status=gt:true",
+		"name=gte:a",
+		"name=isnull:maybe",
+		"created_at=in:2026-01-01",
+		"created_at=gte:yesterday",
+	} {
+		_, err := parse(t, query)
+		assert.ErrorIs(t, err, ErrInvalidFilter, query)
+	}
+}
+
+// go test -run TestApplyConditions
+func TestApplyConditions(t *testing.T) {
+	filter, err := parse(t, "name=in:a,b&status=false")
+	assert.Nil(t, err)
+
+	stmt := filter.ApplyConditions(dryRun(t)).Find(&[]item{}).Statement
+	sql := stmt.SQL.String()
+
+	assert.Contains(t, sql, "items.name IN ($")
+	assert.Contains(t, sql, "status = $")
+	assert.Len(t, stmt.Vars, 3)
+}
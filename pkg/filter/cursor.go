@@ -0,0 +1,277 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor The cursor was not issued by the API or belongs to another sort.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// defaultCursorLimit Size of the cursor pages without a limit.
+const defaultCursorLimit = 20
+
+// CursorSecret Key signing the cursors, set at startup from the configuration shared by every
+// instance, so the cursors survive restarts and are accepted behind a load balancer. The cursor mode
+// is disabled without it, its requests failing with 'ErrInvalidCursor'.
+var CursorSecret []byte
+
+// CursorEnabled Whether the cursors can be signed, with a configured 'CursorSecret'.
+func CursorEnabled() bool {
+	return len(CursorSecret) > 0
+}
+
+// cursor Position after or before an item, by its sort value and its ID.
+type cursor struct {
+	Sort  string      `json:"s"`
+	Order string      `json:"o"`
+	Value interface{} `json:"v"`
+	Time  bool        `json:"t,omitempty"`
+	ID    int64       `json:"i"`
+	Prev  bool        `json:"p,omitempty"`
+}
+
+// keyset Resolved state of the cursor mode, set by 'ApplyOrder' and used by 'Find'.
+type keyset struct {
+	column   string
+	idColumn string
+	desc     bool
+	position *cursor
+}
+
+// CursorMode Whether the list is paginated with cursors, requested with the 'cursor' parameter, empty
+// for the first page.
+func (s *Filter) CursorMode() bool {
+	return s.Cursor != nil
+}
+
+// WithCount Whether the total count is requested, by default only the pages by number count the items.
+func (s *Filter) WithCount() bool {
+	if s.Count != nil {
+		return *s.Count
+	}
+
+	return !s.CursorMode()
+}
+
+// Find Finds the items of the page. In cursor mode the items follow the position of the cursor by the
+// sort value and the ID, without offset, and 'NextCursor' and 'PrevCursor' are set for the pages around.
+func (s *Filter) Find(db *gorm.DB, items interface{}) error {
+	if !s.CursorMode() {
+		return s.ApplyPagination(db).Find(items).Error
+	}
+
+	if db.Error != nil {
+		return db.Error
+	}
+	if s.keyset == nil {
+		return ErrInvalidSort
+	}
+
+	if err := db.Statement.Parse(items); err != nil {
+		return err
+	}
+	sortField := lookUpField(db.Statement.Schema, s.keyset.column)
+	idField := lookUpField(db.Statement.Schema, s.keyset.idColumn)
+	if sortField == nil || idField == nil {
+		return ErrInvalidSort
+	}
+
+	position := s.keyset.position
+	if position != nil {
+		operator := ">"
+		if s.keyset.desc {
+			operator = "<"
+		}
+		db = db.Where(fmt.Sprintf("(%v, %v) %v (?, ?)", s.keyset.column, s.keyset.idColumn, operator), position.Value, position.ID)
+	}
+
+	limit := s.Limit
+	if limit <= 0 {
+		limit = defaultCursorLimit
+	}
+	if err := db.Limit(limit + 1).Find(items).Error; err != nil {
+		return err
+	}
+
+	list := reflect.ValueOf(items).Elem()
+	more := list.Len() > limit
+	if more {
+		list.Set(list.Slice(0, limit))
+	}
+
+	backward := position != nil && position.Prev
+	if backward {
+		for i, j := 0, list.Len()-1; i < j; i, j = i+1, j-1 {
+			first, last := list.Index(i).Interface(), list.Index(j).Interface()
+			list.Index(i).Set(reflect.ValueOf(last))
+			list.Index(j).Set(reflect.ValueOf(first))
+		}
+	}
+
+	if list.Len() == 0 {
+		return nil
+	}
+
+	ctx := db.Statement.Context
+	if more || backward {
+		next, err := s.encodeCursor(ctx, sortField.ValueOf, idField.ValueOf, list.Index(list.Len()-1), false)
+		if err != nil {
+			return err
+		}
+		s.NextCursor = next
+	}
+	if position != nil && (more || !backward) {
+		prev, err := s.encodeCursor(ctx, sortField.ValueOf, idField.ValueOf, list.Index(0), true)
+		if err != nil {
+			return err
+		}
+		s.PrevCursor = prev
+	}
+
+	return nil
+}
+
+// applyCursorOrder Orders by the sort and the ID, reversed to go back from the cursor.
+func (s *Filter) applyCursorOrder(db *gorm.DB, sortable Sortable) *gorm.DB {
+	if !CursorEnabled() {
+		_ = db.AddError(ErrInvalidCursor)
+		return db
+	}
+	if s.Sort == "" {
+		s.Sort = "id"
+	}
+
+	column, found := sortable[s.Sort]
+	idColumn, hasID := sortable["id"]
+	if !found || !hasID {
+		_ = db.AddError(ErrInvalidSort)
+		return db
+	}
+
+	keyset := &keyset{column: column, idColumn: idColumn, desc: s.Order == "desc"}
+	if *s.Cursor != "" {
+		position, err := s.decodeCursor(*s.Cursor)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		keyset.position = position
+		keyset.desc = keyset.desc != position.Prev
+	}
+	s.keyset = keyset
+
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: keyset.desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: idColumn, Raw: true}, Desc: keyset.desc})
+}
+
+type valueOf func(ctx context.Context, value reflect.Value) (interface{}, bool)
+
+func (s *Filter) encodeCursor(ctx context.Context, sortValue, idValue valueOf, item reflect.Value, prev bool) (string, error) {
+	value, _ := sortValue(ctx, item)
+	id, _ := idValue(ctx, item)
+
+	if pointer := reflect.ValueOf(value); pointer.Kind() == reflect.Pointer {
+		if pointer.IsNil() {
+			// The null sort values can not be compared, so such fields are not paginated by cursor.
+			return "", fmt.Errorf("%w: null value of '%v'", ErrInvalidSort, s.Sort)
+		}
+		value = pointer.Elem().Interface()
+	}
+
+	position := &cursor{Sort: s.Sort, Order: s.Order, Value: value, Prev: prev}
+	if moment, ok := value.(time.Time); ok {
+		position.Value, position.Time = moment.Format(time.RFC3339Nano), true
+	}
+
+	switch id := id.(type) {
+	case uint:
+		position.ID = int64(id)
+	case int64:
+		position.ID = id
+	default:
+		return "", fmt.Errorf("unsupported cursor id %T", id)
+	}
+
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded)), nil
+}
+
+func (s *Filter) decodeCursor(value string) (*cursor, error) {
+	encoded, signature, found := strings.Cut(value, ".")
+	if !found || !CursorEnabled() {
+		return nil, ErrInvalidCursor
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, signCursor(encoded)) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	position := &cursor{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(position); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if position.Sort != s.Sort || position.Order != s.Order {
+		return nil, ErrInvalidCursor
+	}
+
+	switch value := position.Value.(type) {
+	case json.Number:
+		if position.Value, err = value.Int64(); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	case string:
+		if position.Time {
+			if position.Value, err = time.Parse(time.RFC3339Nano, value); err != nil {
+				return nil, ErrInvalidCursor
+			}
+		}
+	}
+
+	return position, nil
+}
+
+func signCursor(payload string) []byte {
+	mac := hmac.New(sha256.New, CursorSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// lookUpField Returns the field of the model stored in the column, qualified or not by the table of
+// the model, nil for the columns of the joined tables.
+func lookUpField(model *schema.Schema, column string) *schema.Field {
+	if table, name, found := strings.Cut(column, "."); found {
+		if table != model.Table {
+			return nil
+		}
+		column = name
+	}
+
+	return model.LookUpField(column)
+}
@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type event struct {
+	Id        uint
+	Name      string
+	CreatedAt time.Time
+}
+
+func TestMain(m *testing.M) {
+	CursorSecret = []byte("secret")
+	os.Exit(m.Run())
+}
+
+func eventCursor(t *testing.T, filter *Filter, column string, value event, prev bool) string {
+	model, err := schema.Parse(&event{}, &sync.Map{}, schema.NamingStrategy{})
+	assert.Nil(t, err)
+
+	cursor, err := filter.encodeCursor(context.Background(), model.LookUpField(column).ValueOf, model.LookUpField("id").ValueOf, reflect.ValueOf(value), prev)
+	assert.Nil(t, err)
+
+	return cursor
+}
+
+// capture Returns the last query built by the dry run database.
+func capture(t *testing.T) (*gorm.DB, *string) {
+	db, sql := dryRun(t), new(string)
+	err := db.Callback().Query().After("gorm:query").Register("capture", func(tx *gorm.DB) {
+		*sql = tx.Statement.SQL.String()
+	})
+	assert.Nil(t, err)
+
+	return db, sql
+}
+
+// go test -run TestCursorRoundTrip
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 1, 8, 30, 0, 123456000, time.UTC)
+	filter := &Filter{Sort: "created_at", Order: "desc"}
+
+	position, err := filter.decodeCursor(eventCursor(t, filter, "created_at", event{Id: 7, CreatedAt: created}, true))
+	assert.Nil(t, err)
+	assert.Equal(t, created, position.Value.(time.Time).UTC())
+	assert.Equal(t, int64(7), position.ID)
+	assert.True(t, position.Prev)
+
+	filter = &Filter{Sort: "name", Order: "asc"}
+	position, err = filter.decodeCursor(eventCursor(t, filter, "name", event{Id: 3, Name: "b"}, false))
+	assert.Nil(t, err)
+	assert.Equal(t, "b", position.Value)
+	assert.False(t, position.Prev)
+}
+
+// go test -run TestCursorInvalid
+func TestCursorInvalid(t *testing.T) {
+	filter := &Filter{Sort: "name", Order: "asc"}
+	cursor := eventCursor(t, filter, "name", event{Id: 3, Name: "b"}, false)
+
+	// Tampered payload, unsigned and foreign cursors.
+	for _, value := range []string{"x" + cursor, cursor[:len(cursor)-2], "abc", ""} {
+		_, err := filter.decodeCursor(value)
+		assert.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+
+	// Cursors of another sort or order.
+	_, err := (&Filter{Sort: "name", Order: "desc"}).decodeCursor(cursor)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = (&Filter{Sort: "id", Order: "asc"}).decodeCursor(cursor)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	secret := CursorSecret
+	CursorSecret = []byte("other")
+	defer func() { CursorSecret = secret }()
+	_, err = filter.decodeCursor(cursor)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+// go test -run TestFindCursor
+func TestFindCursor(t *testing.T) {
+	sortable := Sortable{"id": "events.id", "name": "events.name"}
+	empty := ""
+
+	db, sql := capture(t)
+	filter := &Filter{Sort: "name", Order: "asc", Cursor: &empty, Limit: 5}
+	assert.Nil(t, filter.Find(filter.ApplyOrder(db, sortable), &[]event{}))
+	assert.Contains(t, *sql, "ORDER BY events.name,events.id LIMIT $1")
+	assert.NotContains(t, *sql, "WHERE")
+
+	next := eventCursor(t, filter, "name", event{Id: 9, Name: "m"}, false)
+	filter = &Filter{Sort: "name", Order: "asc", Cursor: &next}
+	assert.Nil(t, filter.Find(filter.ApplyOrder(db, sortable), &[]event{}))
+	assert.Contains(t, *sql, "WHERE (events.name, events.id) > ($1, $2) ORDER BY events.name,events.id LIMIT $3")
+
+	prev := eventCursor(t, filter, "name", event{Id: 9, Name: "m"}, true)
+	filter = &Filter{Sort: "name", Order: "asc", Cursor: &prev}
+	assert.Nil(t, filter.Find(filter.ApplyOrder(db, sortable), &[]event{}))
+	assert.Contains(t, *sql, "WHERE (events.name, events.id) < ($1, $2) ORDER BY events.name DESC,events.id DESC")
+
+	// The cursor mode is disabled without a secret.
+	secret := CursorSecret
+	CursorSecret = nil
+	filter = &Filter{Sort: "name", Order: "asc", Cursor: &empty}
+	err := filter.Find(filter.ApplyOrder(dryRun(t), sortable), &[]event{})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = filter.decodeCursor(next)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	CursorSecret = secret
+
+	// The columns of joined tables can not bound the pages.
+	filter = &Filter{Sort: "profile", Order: "asc", Cursor: &empty}
+	err = filter.Find(filter.ApplyOrder(db, Sortable{"id": "events.id", "profile": "profiles.name"}), &[]event{})
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+// go test -run TestWithCount
+func TestWithCount(t *testing.T) {
+	empty, count := "", true
+
+	assert.True(t, (&Filter{}).WithCount())
+	assert.False(t, (&Filter{Cursor: &empty}).WithCount())
+	assert.True(t, (&Filter{Cursor: &empty, Count: &count}).WithCount())
+}
@@ -0,0 +1,212 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFieldset A requested field is malformed or an included relation is not one of the resource.
+var ErrInvalidFieldset = errors.New("invalid fieldset")
+
+// fieldName Field of the response, nested fields are written after their parent, as 'profile.name'.
+var fieldName = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+type (
+	// Relation Relation of a resource embedded in the responses with 'include', loaded by its preload.
+	// The default relations are embedded when 'include' is absent, as before the fieldsets.
+	Relation struct {
+		Preload string
+		Default bool
+	}
+
+	// Relations Relations of a resource by their name in 'include', nested relations after their
+	// parent, as 'profile.permissions'.
+	Relations map[string]Relation
+
+	// Fieldset Sparse fieldset of a response, the fields kept with 'fields', all of them when empty, and
+	// the relations embedded with 'include'.
+	Fieldset struct {
+		Fields  []string
+		Include []string
+	}
+
+	// fieldTree Requested fields by name, nil for the whole field, the nested fields otherwise.
+	fieldTree map[string]fieldTree
+)
+
+// DefaultFieldset Returns the fieldset of the requests without 'fields' and 'include'.
+func DefaultFieldset(relations Relations) *Fieldset {
+	fieldset := &Fieldset{}
+	for name, relation := range relations {
+		if relation.Default {
+			fieldset.Include = append(fieldset.Include, name)
+		}
+	}
+	slices.Sort(fieldset.Include)
+
+	return fieldset
+}
+
+// ParseFieldset Parses the comma separated 'fields' and 'include', nil when 'include' is absent, so
+// the default relations are included. Including a nested relation includes its parents.
+func ParseFieldset(fields string, include *string, relations Relations) (*Fieldset, error) {
+	fieldset := &Fieldset{Fields: splitList(fields)}
+	if err := validateFields(fieldset.Fields); err != nil {
+		return nil, err
+	}
+	if include == nil {
+		fieldset.Include = DefaultFieldset(relations).Include
+		return fieldset, nil
+	}
+
+	for _, name := range splitList(*include) {
+		if _, found := relations[name]; !found {
+			return nil, fmt.Errorf("%w: unknown relation '%v'", ErrInvalidFieldset, name)
+		}
+
+		for parent := name; parent != ""; parent = parentOf(parent) {
+			if _, found := relations[parent]; found && !slices.Contains(fieldset.Include, parent) {
+				fieldset.Include = append(fieldset.Include, parent)
+			}
+		}
+	}
+	slices.Sort(fieldset.Include)
+
+	return fieldset, nil
+}
+
+// Includes Whether the relation is embedded in the response.
+func (s *Fieldset) Includes(relation string) bool {
+	return slices.Contains(s.Include, relation)
+}
+
+// Preload Preloads the included relations, parents before their nested relations.
+func (s *Fieldset) Preload(db *gorm.DB, relations Relations) *gorm.DB {
+	for _, name := range s.Include {
+		if relation, found := relations[name]; found {
+			db = db.Preload(relation.Preload)
+		}
+	}
+
+	return db
+}
+
+// Select Leaves out of the JSON response the fields not requested. The fields of a list apply to its
+// 'items'.
+func (s *Fieldset) Select(body []byte) ([]byte, error) {
+	if len(s.Fields) == 0 || len(body) == 0 {
+		return body, nil
+	}
+
+	var response interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&response); err != nil {
+		return nil, err
+	}
+
+	tree := fieldTree{}
+	for _, field := range s.Fields {
+		tree.add(strings.Split(field, "."))
+	}
+
+	if list, ok := response.(map[string]interface{}); ok {
+		if items, ok := list["items"].([]interface{}); ok {
+			list["items"] = tree.prune(items)
+			return json.Marshal(list)
+		}
+	}
+
+	return json.Marshal(tree.prune(response))
+}
+
+// ApplyPreload Preloads the relations included by the fieldset of the list, the default ones without
+// a fieldset.
+func (s *Filter) ApplyPreload(db *gorm.DB, relations Relations) *gorm.DB {
+	fieldset := s.Fieldset
+	if fieldset == nil {
+		fieldset = DefaultFieldset(relations)
+	}
+
+	return fieldset.Preload(db, relations)
+}
+
+// SetFieldset Sets the fieldset of the list.
+func (s *Filter) SetFieldset(fieldset *Fieldset) {
+	s.Fieldset = fieldset
+}
+
+func (s fieldTree) add(path []string) {
+	nested, found := s[path[0]]
+	if len(path) == 1 {
+		s[path[0]] = nil
+		return
+	}
+	if found && nested == nil {
+		return
+	}
+	if !found {
+		nested = fieldTree{}
+		s[path[0]] = nested
+	}
+
+	nested.add(path[1:])
+}
+
+func (s fieldTree) prune(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			nested, found := s[key]
+			if !found {
+				delete(value, key)
+			} else if nested != nil {
+				value[key] = nested.prune(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = s.prune(item)
+		}
+	}
+
+	return value
+}
+
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		if !fieldName.MatchString(field) {
+			return fmt.Errorf("%w: malformed field '%v'", ErrInvalidFieldset, field)
+		}
+	}
+
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// parentOf Returns the parent of a nested relation, empty for the relations of the resource.
+func parentOf(relation string) string {
+	index := strings.LastIndex(relation, ".")
+	if index < 0 {
+		return ""
+	}
+
+	return relation[:index]
+}
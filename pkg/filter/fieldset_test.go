@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var relations = Relations{
+	"profile":             {Preload: "Profile", Default: true},
+	"profile.permissions": {Preload: "Profile.Permissions"},
+}
+
+// go test -run TestParseFieldset
+func TestParseFieldset(t *testing.T) {
+	fieldset, err := ParseFieldset(" id, name,id ", nil, relations)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "name"}, fieldset.Fields)
+	assert.Equal(t, []string{"profile"}, fieldset.Include)
+
+	include := "profile.permissions"
+	fieldset, err = ParseFieldset("", &include, relations)
+	assert.Nil(t, err)
+	assert.Empty(t, fieldset.Fields)
+	assert.Equal(t, []string{"profile", "profile.permissions"}, fieldset.Include)
+	assert.True(t, fieldset.Includes("profile"))
+
+	include = ""
+	fieldset, err = ParseFieldset("", &include, relations)
+	assert.Nil(t, err)
+	assert.Empty(t, fieldset.Include)
+	assert.False(t, fieldset.Includes("profile"))
+
+	include = "permissions"
+	_, err = ParseFieldset("", &include, relations)
+	assert.ErrorIs(t, err, ErrInvalidFieldset)
+
+	for _, fields := range []string{"name;drop", "profile.", "Name"} {
+		_, err = ParseFieldset(fields, nil, relations)
+		assert.ErrorIs(t, err, ErrInvalidFieldset, fields)
+	}
+}
+
+// go test -run TestFieldsetSelect
+func TestFieldsetSelect(t *testing.T) {
+	item := `{"id":1,"name":"Ana","email":"ana@mail.com","profile":{"id":2,"name":"ADMIN","two_factor":false}}`
+
+	body, err := (&Fieldset{}).Select([]byte(item))
+	assert.Nil(t, err)
+	assert.JSONEq(t, item, string(body))
+
+	fieldset := &Fieldset{Fields: []string{"id", "profile.name", "missing"}}
+	body, err = fieldset.Select([]byte(item))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"id":1,"profile":{"name":"ADMIN"}}`, string(body))
+
+	fieldset = &Fieldset{Fields: []string{"profile.name", "profile"}}
+	body, err = fieldset.Select([]byte(item))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"profile":{"id":2,"name":"ADMIN","two_factor":false}}`, string(body))
+
+	fieldset = &Fieldset{Fields: []string{"name"}}
+	body, err = fieldset.Select([]byte(`{"items":[` + item + `,{"id":3,"name":"Rui"}],"count":2,"next_cursor":"x"}`))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"items":[{"name":"Ana"},{"name":"Rui"}],"count":2,"next_cursor":"x"}`, string(body))
+}
+
+// go test -run TestApplyPreload
+func TestApplyPreload(t *testing.T) {
+	db := (&Filter{}).ApplyPreload(dryRun(t), relations)
+	assert.Contains(t, db.Statement.Preloads, "Profile")
+	assert.NotContains(t, db.Statement.Preloads, "Profile.Permissions")
+
+	filter := &Filter{Fieldset: &Fieldset{Include: []string{"profile", "profile.permissions"}}}
+	db = filter.ApplyPreload(dryRun(t), relations)
+	assert.Contains(t, db.Statement.Preloads, "Profile")
+	assert.Contains(t, db.Statement.Preloads, "Profile.Permissions")
+
+	filter = &Filter{Fieldset: &Fieldset{}}
+	db = filter.ApplyPreload(dryRun(t), relations)
+	assert.Empty(t, db.Statement.Preloads)
+}
@@ -1,16 +1,40 @@
 package filter
 
 import (
-	"fmt"
+	"database/sql"
+	"errors"
 	"os"
 	"slices"
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var orders = []string{"asc", "desc"}
 
+const (
+	SearchLike     = "like"
+	SearchFullText = "fulltext"
+
+	// TextSearchConfig Text search configuration of the full text mode, 'simple' without the accents,
+	// so the words are matched in any language.
+	TextSearchConfig = "msaada"
+	// SearchVectorColumn Generated 'tsvector' column of the resources searched in full text.
+	SearchVectorColumn = "search_vector"
+
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+// htmlEscapes Characters of the document escaped before the highlight, in order, '&' first.
+var htmlEscapes = [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"'", "&#39;"}}
+
+// ErrInvalidSort The requested sort is not one of the sortable fields of the resource.
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// likeEscaper Escapes the wildcards of the search, so they are matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func NewFilter() *Filter {
 	return &Filter{
 		Search: "",
@@ -22,12 +46,40 @@ func NewFilter() *Filter {
 }
 
 type (
+	// Searchable Columns of a resource matched by the search. 'Columns' are matched with 'LIKE', the
+	// full text mode matches the 'Vector' column and highlights the 'Document' expression, the
+	// resources without a vector are matched with 'LIKE' in both modes.
+	Searchable struct {
+		Table    string
+		Columns  []string
+		Vector   string
+		Document string
+	}
+
+	// Sortable Fields of a resource accepted in 'sort', mapped to the column expressions ordering them.
+	// Each repository declares its own, the requested field never reaches the query.
+	Sortable map[string]string
+
 	Filter struct {
-		Search string `query:"search" form:"search" example:"name"`
-		Page   int    `query:"page" form:"page" example:"1"`
-		Limit  int    `query:"limit" form:"limit" example:"10"`
-		Sort   string `query:"sort" form:"sort" example:"'updated_at', 'created_at', 'name' or some other field of the response object"`
-		Order  string `query:"order" form:"order" example:"descending order 'desc' or ascending order 'asc'"`
+		Search     string  `query:"search" form:"search" example:"name"`
+		SearchMode string  `query:"search_mode" form:"search_mode" example:"'like' by default, or 'fulltext' ranked by relevance"`
+		Page       int     `query:"page" form:"page" example:"1"`
+		Limit      int     `query:"limit" form:"limit" example:"10"`
+		Sort       string  `query:"sort" form:"sort" example:"'updated_at', 'created_at', 'name' or some other sortable field of the resource"`
+		Order      string  `query:"order" form:"order" example:"descending order 'desc' or ascending order 'asc'"`
+		Cursor     *string `query:"cursor" form:"cursor" example:"empty for the first page, then the 'next_cursor' or 'prev_cursor' of the response"`
+		Count      *bool   `query:"count" form:"count" example:"true"`
+
+		// Conditions Filters of the declared fields of the resource, parsed by 'ParseConditions'.
+		Conditions []Condition `query:"-" form:"-" swaggerignore:"true"`
+		// NextCursor Cursor of the following page, set by 'Find' in cursor mode.
+		NextCursor string `query:"-" form:"-" swaggerignore:"true"`
+		// PrevCursor Cursor of the previous page, set by 'Find' in cursor mode.
+		PrevCursor string `query:"-" form:"-" swaggerignore:"true"`
+		// Fieldset Fields and relations of the items, set by the fieldset of the list.
+		Fieldset *Fieldset `query:"-" form:"-" swaggerignore:"true"`
+
+		keyset *keyset
 	}
 
 	UserFilter struct {
@@ -36,32 +88,83 @@ type (
 	}
 )
 
-func (s *Filter) ApplySearchLike(db *gorm.DB, columns ...string) *gorm.DB {
-	if len(columns) > 0 && s.Search != "" {
-		whereLike := func(column, value string) string {
-			return fmt.Sprintf("unaccent(LOWER(%v)) LIKE unaccent(LOWER('%%%v%%'))", column, value)
-		}
-		where := ""
+// SearchDocument Returns the text expression of the columns searched in full text.
+func SearchDocument(columns ...string) string {
+	document := make([]string, len(columns))
+	for i, column := range columns {
+		document[i] = "coalesce(" + column + ", '')"
+	}
 
-		for i, column := range columns {
-			if i > 0 {
-				where = where + " or "
-			}
+	return strings.Join(document, " || ' ' || ")
+}
+
+// EscapeDocument Returns the SQL of the document with its HTML escaped, so the only markup of the
+// highlight is its '<mark>' tags.
+func EscapeDocument(document string) string {
+	for _, escape := range htmlEscapes {
+		document = "replace(" + document + ", '" + strings.ReplaceAll(escape[0], "'", "''") + "', '" + escape[1] + "')"
+	}
 
-			where = where + whereLike(column, s.Search)
-		}
+	return document
+}
 
-		if where != "" {
-			return db.Where(where)
-		}
+// ApplySearch Matches the search in the columns of the mode. The full text mode selects the highlighted
+// document as 'headline', with the HTML of the document escaped, and orders by the rank of the match,
+// except in cursor mode.
+func (s *Filter) ApplySearch(db *gorm.DB, searchable Searchable) *gorm.DB {
+	if s.Search == "" {
+		return db
+	}
+	if !strings.EqualFold(s.SearchMode, SearchFullText) || searchable.Vector == "" {
+		return s.ApplySearchLike(db, searchable.Columns...)
 	}
 
-	return db
+	query := "websearch_to_tsquery('" + TextSearchConfig + "', @search)"
+	search := sql.Named("search", s.Search)
+
+	db = db.Where(searchable.Vector+" @@ "+query, search).Select(searchable.Table+".*, "+
+		"ts_headline('"+TextSearchConfig+"', "+EscapeDocument(searchable.Document)+", "+query+", '"+headlineOptions+"') AS headline, "+
+		"ts_rank("+searchable.Vector+", "+query+") AS search_rank", search)
+	if s.CursorMode() {
+		return db
+	}
+
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "search_rank", Raw: true}, Desc: true})
 }
 
-func (s *Filter) ApplyOrder(db *gorm.DB) *gorm.DB {
+// ApplySearchLike Matches the search in any of the columns, ignoring case and accents. The columns are
+// given by the repository, the search is bound as a parameter with its wildcards escaped.
+func (s *Filter) ApplySearchLike(db *gorm.DB, columns ...string) *gorm.DB {
+	if len(columns) == 0 || s.Search == "" {
+		return db
+	}
+
+	where := make([]string, len(columns))
+	for i, column := range columns {
+		where[i] = "unaccent(LOWER(" + column + ")) LIKE unaccent(LOWER(@search)) ESCAPE '\\'"
+	}
+
+	return db.Where("("+strings.Join(where, " OR ")+")", sql.Named("search", "%"+likeEscaper.Replace(s.Search)+"%"))
+}
+
+// ApplyOrder Orders by the column of the requested sort, adding 'ErrInvalidSort' to the query when the
+// field is not sortable. No order is applied without a sort, except in cursor mode ordering by the ID.
+func (s *Filter) ApplyOrder(db *gorm.DB, sortable Sortable) *gorm.DB {
 	s.check()
-	return db.Order(fmt.Sprintf("%v %v", s.Sort, s.Order))
+	if s.CursorMode() {
+		return s.applyCursorOrder(db, sortable)
+	}
+	if s.Sort == "" {
+		return db
+	}
+
+	column, found := sortable[s.Sort]
+	if !found {
+		_ = db.AddError(ErrInvalidSort)
+		return db
+	}
+
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: s.Order == "desc"})
 }
 
 func (s *Filter) ApplyPagination(db *gorm.DB) *gorm.DB {
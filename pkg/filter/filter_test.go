@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 // go test -run TestNewFilter
@@ -21,3 +23,95 @@ func TestNewFilter(t *testing.T) {
 	assert.Equal(t, os.Getenv("API_DEFAULT_SORT"), filter.Sort)
 	assert.Equal(t, os.Getenv("API_DEFAULT_ORDER"), filter.Order)
 }
+
+type item struct {
+	Id   uint
+	Name string
+}
+
+func dryRun(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	assert.Nil(t, err)
+
+	return db
+}
+
+// go test -run TestApplySearchLike
+func TestApplySearchLike(t *testing.T) {
+	filter := &Filter{Search: `50%_off\'; DROP TABLE items; --`}
+
+	stmt := filter.ApplySearchLike(dryRun(t), "name", "code").Find(&[]item{}).Statement
+	sql := stmt.SQL.String()
+
+	assert.Contains(t, sql, "unaccent(LOWER(name)) LIKE unaccent(LOWER($1)) ESCAPE '\\' OR unaccent(LOWER(code)) LIKE unaccent(LOWER($2))")
+	assert.NotContains(t, sql, "DROP")
+	assert.Equal(t, []interface{}{`%50\%\_off\\'; DROP TABLE items; --%`, `%50\%\_off\\'; DROP TABLE items; --%`}, stmt.Vars)
+
+	// Without a search nothing is added.
+	stmt = (&Filter{}).ApplySearchLike(dryRun(t), "name").Find(&[]item{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "WHERE")
+}
+
+// go test -run TestApplyOrder
+func TestApplyOrder(t *testing.T) {
+	sortable := Sortable{"name": "items.name"}
+
+	filter := &Filter{Sort: "name", Order: "DESC"}
+	tx := filter.ApplyOrder(dryRun(t), sortable).Find(&[]item{})
+	assert.Nil(t, tx.Error)
+	assert.Contains(t, tx.Statement.SQL.String(), "ORDER BY items.name DESC")
+
+	filter = &Filter{Sort: "name; DROP TABLE items", Order: "asc"}
+	tx = filter.ApplyOrder(dryRun(t), sortable).Find(&[]item{})
+	assert.ErrorIs(t, tx.Error, ErrInvalidSort)
+	assert.NotContains(t, tx.Statement.SQL.String(), "DROP")
+
+	filter = &Filter{Order: "asc"}
+	tx = filter.ApplyOrder(dryRun(t), sortable).Find(&[]item{})
+	assert.Nil(t, tx.Error)
+	assert.NotContains(t, tx.Statement.SQL.String(), "ORDER BY")
+}
+
+// go test -run TestEscapeDocument
+func TestEscapeDocument(t *testing.T) {
+	assert.Equal(t, `replace(replace(replace(replace(replace(items.name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`,
+		EscapeDocument("items.name"))
+}
+
+// go test -run TestApplySearch
+func TestApplySearch(t *testing.T) {
+	searchable := Searchable{
+		Table:    "items",
+		Columns:  []string{"name"},
+		Vector:   "items." + SearchVectorColumn,
+		Document: SearchDocument("items.name", "items.code"),
+	}
+
+	filter := &Filter{Search: "água fria", SearchMode: "FullText"}
+	stmt := filter.ApplySearch(dryRun(t), searchable).Find(&[]item{}).Statement
+	sql := stmt.SQL.String()
+
+	assert.Contains(t, sql, "SELECT items.*, ts_headline('msaada', "+EscapeDocument(searchable.Document)+", websearch_to_tsquery('msaada', $1)")
+	assert.Contains(t, sql, "ts_rank(items.search_vector, websearch_to_tsquery('msaada', $")
+	assert.Contains(t, sql, "WHERE items.search_vector @@ websearch_to_tsquery('msaada', $")
+	assert.Contains(t, sql, "ORDER BY search_rank DESC")
+	assert.Equal(t, []interface{}{"água fria", "água fria", "água fria"}, stmt.Vars)
+
+	// The count keeps the match without the headline.
+	var count int64
+	stmt = filter.ApplySearch(dryRun(t), searchable).Model(&item{}).Count(&count).Statement
+	assert.Contains(t, stmt.SQL.String(), "SELECT count(*) FROM \"items\" WHERE items.search_vector @@")
+
+	// The cursor mode keeps its order and the resources without a vector are matched with 'LIKE'.
+	empty := ""
+	filter.Cursor = &empty
+	stmt = filter.ApplySearch(dryRun(t), searchable).Find(&[]item{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "ORDER BY")
+
+	stmt = filter.ApplySearch(dryRun(t), Searchable{Table: "items", Columns: []string{"name"}}).Find(&[]item{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "LIKE")
+}
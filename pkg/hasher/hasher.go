@@ -0,0 +1,150 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+	bcryptPrefix   = "$2"
+)
+
+var ErrInvalidHash = errors.New("invalid password hash")
+
+type (
+	// Hasher Algorithm of the new password hashes, 'NeedsRehash' reports the hashes made by another
+	// algorithm or with other parameters.
+	Hasher interface {
+		Hash(password string) (string, error)
+		NeedsRehash(encoded string) bool
+	}
+
+	// Argon2id Parameters of argon2id, 'Memory' is in KiB.
+	Argon2id struct {
+		Memory     uint32
+		Time       uint32
+		Threads    uint8
+		SaltLength uint32
+		KeyLength  uint32
+	}
+
+	// Bcrypt Cost of bcrypt, only the first 72 bytes of the password are used.
+	Bcrypt struct {
+		Cost int
+	}
+)
+
+// Default Hasher of the new passwords, replaced at startup by the configured one.
+var Default Hasher = DefaultArgon2id()
+
+// DefaultArgon2id Returns the argon2id parameters recommended by OWASP.
+func DefaultArgon2id() *Argon2id {
+	return &Argon2id{
+		Memory:     19 * 1024,
+		Time:       2,
+		Threads:    1,
+		SaltLength: 16,
+		KeyLength:  32,
+	}
+}
+
+// Hash Hashes the password with the default hasher.
+func Hash(password string) (string, error) {
+	return Default.Hash(password)
+}
+
+// NeedsRehash Reports whether the hash is outdated for the default hasher.
+func NeedsRehash(encoded string) bool {
+	return Default.NeedsRehash(encoded)
+}
+
+// Verify Reports whether the password matches the hash, the algorithm is told by the hash prefix.
+func Verify(encoded, password string) bool {
+	switch {
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false
+		}
+
+		derived := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLength)
+		return subtle.ConstantTimeCompare(derived, key) == 1
+	case strings.HasPrefix(encoded, bcryptPrefix):
+		return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+	}
+
+	return false
+}
+
+// Hash Returns the PHC string '$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>'.
+func (s *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, s.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, s.Time, s.Memory, s.Threads, s.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, s.Memory, s.Time, s.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// NeedsRehash Implementation of 'NeedsRehash'.
+func (s *Argon2id) NeedsRehash(encoded string) bool {
+	params, salt, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	params.SaltLength = uint32(len(salt))
+	return *params != *s
+}
+
+func decodeArgon2id(encoded string) (*Argon2id, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(encoded, argon2idPrefix), "$")
+	if !strings.HasPrefix(encoded, argon2idPrefix) || len(parts) != 4 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	params := &Argon2id{}
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
+// Hash Implementation of 'Hash'.
+func (s *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.Cost)
+	return string(hash), err
+}
+
+// NeedsRehash Implementation of 'NeedsRehash'.
+func (s *Bcrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != s.Cost
+}
@@ -0,0 +1,78 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fastArgon2id Small parameters keeping the tests fast.
+var fastArgon2id = &Argon2id{Memory: 64, Time: 1, Threads: 1, SaltLength: 16, KeyLength: 32}
+
+// go test -run TestArgon2id
+func TestArgon2id(t *testing.T) {
+	hash, err := fastArgon2id.Hash("river7stone")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	assert.True(t, Verify(hash, "river7stone"))
+	assert.False(t, Verify(hash, "river7stones"))
+
+	other, _ := fastArgon2id.Hash("river7stone")
+	assert.NotEqual(t, hash, other)
+}
+
+// go test -run TestBcrypt
+func TestBcrypt(t *testing.T) {
+	hasher := &Bcrypt{Cost: bcrypt.MinCost}
+	hash, err := hasher.Hash("river7stone")
+	assert.Nil(t, err)
+
+	assert.True(t, Verify(hash, "river7stone"))
+	assert.False(t, Verify(hash, "river7stones"))
+}
+
+// go test -run TestVerifyInvalid
+func TestVerifyInvalid(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"river7stone",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+		"$2a$10$invalid",
+	} {
+		assert.False(t, Verify(hash, "river7stone"), hash)
+	}
+}
+
+// go test -run TestNeedsRehash
+func TestNeedsRehash(t *testing.T) {
+	legacy, _ := (&Bcrypt{Cost: bcrypt.MinCost}).Hash("river7stone")
+	current, _ := fastArgon2id.Hash("river7stone")
+
+	assert.True(t, fastArgon2id.NeedsRehash(legacy))
+	assert.False(t, fastArgon2id.NeedsRehash(current))
+
+	stronger := *fastArgon2id
+	stronger.Time = 2
+	assert.True(t, stronger.NeedsRehash(current))
+
+	assert.False(t, (&Bcrypt{Cost: bcrypt.MinCost}).NeedsRehash(legacy))
+	assert.True(t, (&Bcrypt{Cost: bcrypt.MinCost + 1}).NeedsRehash(legacy))
+	assert.True(t, (&Bcrypt{Cost: bcrypt.MinCost}).NeedsRehash(current))
+}
+
+// go test -run TestDefault
+func TestDefault(t *testing.T) {
+	previous := Default
+	defer func() { Default = previous }()
+
+	Default = fastArgon2id
+	hash, err := Hash("river7stone")
+	assert.Nil(t, err)
+	assert.False(t, NeedsRehash(hash))
+	assert.True(t, Verify(hash, "river7stone"))
+}
@@ -1,11 +1,18 @@
 package httphelper
 
 const (
-	LocalObject string = "localObject"
-	LocalUser   string = "localUser"
-	LocalLang   string = "localLang"
-	LocalDTO    string = "localDTO"
-	LocalFilter string = "localFilter"
-	ParamID     string = "id"
-	ParamMail   string = "email"
+	LocalObject       string = "localObject"
+	LocalUser         string = "localUser"
+	LocalSession      string = "localSession"
+	LocalImpersonator string = "localImpersonator"
+	LocalClaims       string = "localClaims"
+	LocalApiKey       string = "localApiKey"
+	LocalIP           string = "localIP"
+	LocalLang         string = "localLang"
+	LocalDTO          string = "localDTO"
+	LocalFilter       string = "localFilter"
+	LocalFieldset     string = "localFieldset"
+	LocalRequestID    string = "requestid"
+	ParamID           string = "id"
+	ParamMail         string = "email"
 )
@@ -0,0 +1,62 @@
+package httphelper
+
+import (
+	"net"
+	"strings"
+)
+
+// TrustedProxies Addresses and networks of the proxies in front of the API.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies Parses the addresses and CIDR networks of the proxies, skipping the malformed ones.
+func ParseTrustedProxies(proxies []string) TrustedProxies {
+	trusted := TrustedProxies{}
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, network)
+			continue
+		}
+
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ipv4 := ip.To4(); ipv4 != nil {
+				ip, bits = ipv4, 8*net.IPv4len
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+
+	return trusted
+}
+
+// Contains Whether the address is one of the trusted proxies.
+func (s TrustedProxies) Contains(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range s {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ForwardedClient Returns the client of a 'X-Forwarded-For' header, the right-most address not of a
+// trusted proxy. The left-most entries are sent by the client, so they can be forged.
+func (s TrustedProxies) ForwardedClient(header string) string {
+	entries := strings.Split(header, ",")
+
+	client := ""
+	for i := len(entries) - 1; i >= 0; i-- {
+		client = strings.TrimSpace(entries[i])
+		if !s.Contains(client) {
+			break
+		}
+	}
+
+	return client
+}
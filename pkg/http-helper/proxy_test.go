@@ -0,0 +1,30 @@
+package httphelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestTrustedProxies
+func TestTrustedProxies(t *testing.T) {
+	trusted := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.10", "::1", "proxy"})
+	assert.Len(t, trusted, 3)
+
+	assert.True(t, trusted.Contains("10.20.30.40"))
+	assert.True(t, trusted.Contains("192.168.1.10"))
+	assert.True(t, trusted.Contains("::1"))
+	assert.False(t, trusted.Contains("192.168.1.11"))
+	assert.False(t, trusted.Contains("unknown"))
+}
+
+// go test -run TestForwardedClient
+func TestForwardedClient(t *testing.T) {
+	trusted := ParseTrustedProxies([]string{"10.0.0.0/8"})
+
+	assert.Equal(t, "203.0.113.7", trusted.ForwardedClient("203.0.113.7"))
+	assert.Equal(t, "203.0.113.7", trusted.ForwardedClient("203.0.113.7, 10.0.0.2"))
+	assert.Equal(t, "203.0.113.7", trusted.ForwardedClient("1.2.3.4, 203.0.113.7, 10.0.0.3, 10.0.0.2"))
+	assert.Equal(t, "10.0.0.3", trusted.ForwardedClient("10.0.0.3,10.0.0.2"))
+	assert.Equal(t, "forged", trusted.ForwardedClient("1.2.3.4, forged, 10.0.0.2"))
+}
@@ -0,0 +1,64 @@
+package ipbinding
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+const (
+	// Off Tokens are accepted from any address.
+	Off Mode = "off"
+	// Subnet Tokens are accepted from the same /24 IPv4 or /64 IPv6 network.
+	Subnet Mode = "subnet"
+	// Strict Tokens are only accepted from the address they were issued to.
+	Strict Mode = "strict"
+
+	ipv4SubnetBits = 24
+	ipv6SubnetBits = 64
+)
+
+var ErrInvalidMode = errors.New("invalid ip binding mode")
+
+// Mode How strictly a token is bound to the client address it was issued to.
+type Mode string
+
+// ParseMode Returns the mode by name, an empty name is 'Strict'.
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "":
+		return Strict, nil
+	case Off, Subnet, Strict:
+		return mode, nil
+	}
+
+	return "", ErrInvalidMode
+}
+
+// network Returns the network of the address with the subnet size of its family.
+func network(ip net.IP) *net.IPNet {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		mask := net.CIDRMask(ipv4SubnetBits, 8*net.IPv4len)
+		return &net.IPNet{IP: ipv4.Mask(mask), Mask: mask}
+	}
+
+	mask := net.CIDRMask(ipv6SubnetBits, 8*net.IPv6len)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// Match Reports whether a token bound to the address 'bound' is accepted from 'current'.
+func (m Mode) Match(bound, current string) bool {
+	switch m {
+	case Off:
+		return true
+	case Subnet:
+		boundIP, currentIP := net.ParseIP(bound), net.ParseIP(current)
+		if boundIP == nil || currentIP == nil {
+			return bound == current
+		}
+
+		return network(boundIP).Contains(currentIP)
+	}
+
+	return bound == current
+}
@@ -0,0 +1,43 @@
+package ipbinding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestParseMode
+func TestParseMode(t *testing.T) {
+	for name, expected := range map[string]Mode{"": Strict, "off": Off, " Subnet ": Subnet, "STRICT": Strict} {
+		mode, err := ParseMode(name)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	_, err := ParseMode("loose")
+	assert.ErrorIs(t, err, ErrInvalidMode)
+}
+
+// go test -run TestMatchOff
+func TestMatchOff(t *testing.T) {
+	assert.True(t, Off.Match("10.0.0.1", "192.168.0.1"))
+	assert.True(t, Off.Match("10.0.0.1", ""))
+}
+
+// go test -run TestMatchStrict
+func TestMatchStrict(t *testing.T) {
+	assert.True(t, Strict.Match("10.0.0.1", "10.0.0.1"))
+	assert.False(t, Strict.Match("10.0.0.1", "10.0.0.2"))
+	assert.False(t, Mode("").Match("10.0.0.1", "10.0.0.2"))
+}
+
+// go test -run TestMatchSubnet
+func TestMatchSubnet(t *testing.T) {
+	assert.True(t, Subnet.Match("197.232.10.4", "197.232.10.250"))
+	assert.False(t, Subnet.Match("197.232.10.4", "197.232.11.4"))
+	assert.True(t, Subnet.Match("2001:db8:1:2::10", "2001:db8:1:2:ffff::1"))
+	assert.False(t, Subnet.Match("2001:db8:1:2::10", "2001:db8:1:3::10"))
+	assert.False(t, Subnet.Match("197.232.10.4", "2001:db8:1:2::10"))
+	assert.True(t, Subnet.Match("::ffff:197.232.10.4", "197.232.10.9"))
+	assert.False(t, Subnet.Match("invalid", "197.232.10.9"))
+}
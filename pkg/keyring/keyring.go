@@ -0,0 +1,250 @@
+package keyring
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no signing key")
+	ErrUnknownKey   = errors.New("unknown signing key")
+	ErrInvalidKey   = errors.New("invalid rsa key")
+)
+
+type (
+	// Key RSA key identified by its 'kid', keys without the private part only verify tokens.
+	Key struct {
+		ID      string
+		Private *rsa.PrivateKey
+		Public  *rsa.PublicKey
+	}
+
+	// Source Loads the keys of a keyring, the first one holding a private key signs new tokens.
+	Source func(context.Context) ([]*Key, error)
+
+	// JWK Public RSA key in the RFC 7517 format.
+	JWK struct {
+		Kty string `json:"kty" example:"RSA"`
+		Use string `json:"use" example:"sig"`
+		Alg string `json:"alg" example:"RS256"`
+		Kid string `json:"kid" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+		N   string `json:"n" example:"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"`
+		E   string `json:"e" example:"AQAB"`
+	}
+
+	// JWKS Set of public keys published to verify the tokens.
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+
+	// Keyring Keys of one token type, safe for concurrent use and reloaded from its source.
+	Keyring struct {
+		mu      sync.RWMutex
+		source  Source
+		current *Key
+		keys    map[string]*Key
+	}
+)
+
+// New Creates the keyring and loads its keys.
+func New(ctx context.Context, source Source) (*Keyring, error) {
+	keyring := &Keyring{source: source}
+	return keyring, keyring.Reload(ctx)
+}
+
+// NewKey Wraps the private key, identified by the thumbprint of its public part.
+func NewKey(private *rsa.PrivateKey) *Key {
+	return &Key{
+		ID:      Thumbprint(&private.PublicKey),
+		Private: private,
+		Public:  &private.PublicKey,
+	}
+}
+
+// GenerateKey Generates a new RSA key.
+func GenerateKey(bits int) (*Key, error) {
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKey(private), nil
+}
+
+// ParseKey Parses a PKCS #1 or PKCS #8 PEM private key.
+func ParseKey(encoded []byte) (*Key, error) {
+	private, err := jwt.ParseRSAPrivateKeyFromPEM(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err.Error())
+	}
+
+	return NewKey(private), nil
+}
+
+// ParseBase64Key Parses a base64 encoded PEM private key, as stored in the environment variables.
+func ParseBase64Key(encoded string) (*Key, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err.Error())
+	}
+
+	return ParseKey(decoded)
+}
+
+// EncodePrivate Returns the private key as a PKCS #1 PEM.
+func (k *Key) EncodePrivate() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k.Private)})
+}
+
+// JWK Returns the public key in the JWK format.
+func (k *Key) JWK() JWK {
+	return JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Kid: k.ID,
+		N:   base64.RawURLEncoding.EncodeToString(k.Public.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.Public.E)).Bytes()),
+	}
+}
+
+// ParseJWK Parses a public RSA key in the JWK format, kept only to verify tokens.
+func ParseJWK(jwk JWK) (*Key, error) {
+	if jwk.Kty != "RSA" {
+		return nil, fmt.Errorf("%w: unexpected key type %v", ErrInvalidKey, jwk.Kty)
+	}
+
+	n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+	e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+	if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("%w: invalid jwk %v", ErrInvalidKey, jwk.Kid)
+	}
+
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	return &Key{ID: jwk.Kid, Public: public}, nil
+}
+
+// Thumbprint Returns the RFC 7638 thumbprint of the public key, used as its 'kid'.
+func Thumbprint(public *rsa.PublicKey) string {
+	jwk := (&Key{Public: public}).JWK()
+	digest := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// Reload Replaces the keys by the ones of the source, the previous keys are kept on errors.
+func (k *Keyring) Reload(ctx context.Context) error {
+	loaded, err := k.source(ctx)
+	if err != nil {
+		return err
+	}
+
+	var current *Key
+	keys := make(map[string]*Key, len(loaded))
+	for _, key := range loaded {
+		if current == nil && key.Private != nil {
+			current = key
+		}
+		keys[key.ID] = key
+	}
+
+	if current == nil {
+		return ErrNoSigningKey
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.current, k.keys = current, keys
+	return nil
+}
+
+// Watch Reloads the keys every interval until the context is done, picking up rotations made by other instances.
+func (k *Keyring) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Reload(ctx); err != nil {
+				log.Println(err.Error())
+			}
+		}
+	}
+}
+
+// Sign Signs the claims with the current key, setting its 'kid' header.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	current := k.current
+	k.mu.RUnlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = current.ID
+	return token.SignedString(current.Private)
+}
+
+// KeyID Returns the 'kid' of the current key, the one signing the new tokens.
+func (k *Keyring) KeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.current.ID
+}
+
+// Keyfunc Returns the key matching the 'kid' of the token, tokens issued before the 'kid' header
+// existed are verified with the current key.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	kid, found := token.Header["kid"].(string)
+	if !found {
+		return k.current.Public, nil
+	}
+
+	key, found := k.keys[kid]
+	if !found {
+		return nil, ErrUnknownKey
+	}
+
+	return key.Public, nil
+}
+
+// JWKS Returns the public keys of the keyring.
+func (k *Keyring) JWKS() *JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	jwks := &JWKS{Keys: []JWK{k.current.JWK()}}
+	for _, key := range k.keys {
+		if key != k.current {
+			jwks.Keys = append(jwks.Keys, key.JWK())
+		}
+	}
+
+	slices.SortFunc(jwks.Keys[1:], func(a, b JWK) int {
+		return strings.Compare(a.Kid, b.Kid)
+	})
+	return jwks
+}
@@ -0,0 +1,127 @@
+package keyring
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func staticSource(keys ...*Key) Source {
+	return func(context.Context) ([]*Key, error) {
+		return keys, nil
+	}
+}
+
+// go test -run TestThumbprint
+func TestThumbprint(t *testing.T) {
+	// RFC 7638 section 3.1 example key.
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", Thumbprint(public))
+}
+
+// go test -run TestEncodeKey
+func TestEncodeKey(t *testing.T) {
+	key, err := GenerateKey(1024)
+	assert.Nil(t, err)
+
+	parsed, err := ParseKey(key.EncodePrivate())
+	assert.Nil(t, err)
+	assert.Equal(t, key.ID, parsed.ID)
+
+	_, err = ParseBase64Key("invalid")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+// go test -run TestParseJWK
+func TestParseJWK(t *testing.T) {
+	key, _ := GenerateKey(1024)
+
+	parsed, err := ParseJWK(key.JWK())
+	assert.Nil(t, err)
+	assert.Equal(t, key.ID, parsed.ID)
+	assert.True(t, key.Public.Equal(parsed.Public))
+
+	_, err = ParseJWK(JWK{Kty: "EC", Kid: "ec"})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = ParseJWK(JWK{Kty: "RSA", Kid: "empty"})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+// go test -run TestKeyringRotation
+func TestKeyringRotation(t *testing.T) {
+	old, _ := GenerateKey(1024)
+	current, _ := GenerateKey(1024)
+
+	keyring, err := New(context.Background(), staticSource(old))
+	assert.Nil(t, err)
+
+	oldSigned, err := keyring.Sign(jwt.MapClaims{"sub": "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, old.ID, keyring.KeyID())
+
+	retired := &Key{ID: old.ID, Public: old.Public}
+	keyring.source = staticSource(current, retired)
+	assert.Nil(t, keyring.Reload(context.Background()))
+
+	token, err := jwt.Parse(oldSigned, keyring.Keyfunc)
+	assert.Nil(t, err)
+	assert.Equal(t, old.ID, token.Header["kid"])
+
+	signed, _ := keyring.Sign(jwt.MapClaims{"sub": "1"})
+	token, err = jwt.Parse(signed, keyring.Keyfunc)
+	assert.Nil(t, err)
+	assert.Equal(t, current.ID, token.Header["kid"])
+	assert.Equal(t, current.ID, keyring.KeyID())
+
+	jwks := keyring.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, current.ID, jwks.Keys[0].Kid)
+
+	keyring.source = staticSource(current)
+	assert.Nil(t, keyring.Reload(context.Background()))
+
+	_, err = jwt.Parse(oldSigned, keyring.Keyfunc)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+// go test -run TestKeyringUnknownKey
+func TestKeyringUnknownKey(t *testing.T) {
+	current, _ := GenerateKey(1024)
+	other, _ := GenerateKey(1024)
+	keyring, _ := New(context.Background(), staticSource(current))
+
+	foreign, _ := (&Keyring{current: other}).Sign(jwt.MapClaims{})
+	_, err := jwt.Parse(foreign, keyring.Keyfunc)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+// go test -run TestKeyringWrongAlgorithm
+func TestKeyringWrongAlgorithm(t *testing.T) {
+	current, _ := GenerateKey(1024)
+	keyring, _ := New(context.Background(), staticSource(current))
+
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{}).SignedString([]byte("secret"))
+	_, err := jwt.Parse(signed, keyring.Keyfunc)
+	assert.NotNil(t, err)
+}
+
+// go test -run TestKeyringWithoutSigningKey
+func TestKeyringWithoutSigningKey(t *testing.T) {
+	current, _ := GenerateKey(1024)
+	_, err := New(context.Background(), staticSource(&Key{ID: current.ID, Public: current.Public}))
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = New(context.Background(), func(context.Context) ([]*Key, error) {
+		return nil, errors.New("unavailable")
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+)
+
+// NewLogSender Creates a fake sender for local runs that appends the messages to the file,
+// or writes them to the standard logger when path is empty.
+func NewLogSender(path string) Sender {
+	return &logSender{
+		path: path,
+	}
+}
+
+type logSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *logSender) Send(ctx context.Context, message *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	content := message.Bytes("noreply@localhost")
+	if s.path == "" {
+		log.Printf("mail not sent, logged instead:\n%s\n", content)
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	_, err = file.Write(append(content, '\n', '\n'))
+	return err
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"time"
+)
+
+type (
+	Message struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	// Sender Delivers plain text messages, implementations must be safe for concurrent use.
+	Sender interface {
+		Send(context.Context, *Message) error
+	}
+)
+
+// Bytes Formats the message as a RFC 5322 plain text mail.
+func (m *Message) Bytes(from string) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(m.Body)
+
+	return buf.Bytes()
+}
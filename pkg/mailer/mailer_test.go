@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestMessageBytes
+func TestMessageBytes(t *testing.T) {
+	message := &Message{To: "john.cena@email.com", Subject: "Redefinição de senha", Body: "secret link"}
+	content := string(message.Bytes("noreply@email.com"))
+
+	assert.Contains(t, content, "From: noreply@email.com\r\n")
+	assert.Contains(t, content, "To: john.cena@email.com\r\n")
+	assert.Contains(t, content, "Subject: =?utf-8?q?")
+	assert.True(t, strings.HasSuffix(content, "\r\n\r\nsecret link"))
+}
+
+// go test -run TestLogSender
+func TestLogSender(t *testing.T) {
+	file := path.Join(t.TempDir(), "mail.log")
+	sender := NewLogSender(file)
+
+	assert.Nil(t, sender.Send(context.Background(), &Message{To: "first@email.com", Subject: "First", Body: "first body"}))
+	assert.Nil(t, sender.Send(context.Background(), &Message{To: "second@email.com", Subject: "Second", Body: "second body"}))
+
+	content, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "To: first@email.com")
+	assert.Contains(t, string(content), "second body")
+}
+
+// go test -run TestLogSenderCanceled
+func TestLogSenderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewLogSender("").Send(ctx, &Message{To: "first@email.com"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+// NewSMTPSender Creates a sender that delivers through the SMTP server, authentication is skipped without username.
+func NewSMTPSender(host, port, username, password, from string) Sender {
+	return &smtpSender{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+type smtpSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (s *smtpSender) Send(ctx context.Context, message *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	return smtp.SendMail(s.addr, auth, s.from, []string{message.To}, message.Bytes(s.from))
+}
@@ -0,0 +1,300 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+)
+
+// keysRefresh Minimum time between two downloads of the provider keys caused by an unknown 'kid'.
+const keysRefresh = time.Minute
+
+var (
+	ErrDiscovery    = errors.New("oidc discovery failed")
+	ErrExchange     = errors.New("oidc code exchange failed")
+	ErrInvalidToken = errors.New("invalid oidc id token")
+	ErrInvalidNonce = errors.New("invalid oidc nonce")
+)
+
+type (
+	// Config Registration of the client at the identity provider.
+	Config struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+		Client       *http.Client
+	}
+
+	// Claims Identity claims of the ID token.
+	Claims struct {
+		jwt.RegisteredClaims
+		Nonce         string `json:"nonce"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+
+	discovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JwksURI               string `json:"jwks_uri"`
+	}
+
+	tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	// Provider Client of the authorization code flow with PKCE, the provider metadata is discovered
+	// on first use and its keys are downloaded again when a token is signed by an unknown key.
+	Provider struct {
+		config    Config
+		mu        sync.Mutex
+		discovery *discovery
+		keys      map[string]*rsa.PublicKey
+		fetched   time.Time
+	}
+)
+
+// New Creates the provider client, no request is made until it is used.
+func New(config Config) *Provider {
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{config: config}
+}
+
+// RandomString Returns a random URL safe value, used for the state, the nonce and the PKCE verifier.
+func RandomString() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// Challenge Returns the S256 PKCE challenge of the verifier.
+func Challenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, value interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v from %v", res.StatusCode, endpoint)
+	}
+
+	return json.NewDecoder(res.Body).Decode(value)
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	metadata := &discovery{}
+	endpoint := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, endpoint, metadata); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err.Error())
+	}
+
+	if metadata.Issuer != p.config.Issuer || metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksURI == "" {
+		return nil, fmt.Errorf("%w: invalid metadata of %v", ErrDiscovery, p.config.Issuer)
+	}
+
+	p.discovery = metadata
+	return metadata, nil
+}
+
+// AuthCodeURL Returns the address of the provider login, the verifier must be kept to exchange the code.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange Trades the authorization code for the tokens, returning the raw ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	res, err := p.config.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrExchange, err.Error())
+	}
+	defer res.Body.Close()
+
+	token := &tokenResponse{}
+	if err := json.NewDecoder(res.Body).Decode(token); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrExchange, err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK || token.IDToken == "" {
+		return "", fmt.Errorf("%w: %v %v", ErrExchange, token.Error, token.ErrorDescription)
+	}
+
+	return token.IDToken, nil
+}
+
+// fetchKeys Downloads the provider keys, at most once per 'keysRefresh' unless none was loaded.
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil && time.Since(p.fetched) < keysRefresh {
+		return nil
+	}
+
+	jwks := &keyring.JWKS{}
+	if err := p.getJSON(ctx, jwksURI, jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		// Keys of other types or uses are not used to sign ID tokens with RSA.
+		if key, err := keyring.ParseJWK(jwk); err == nil && (jwk.Use == "" || jwk.Use == "sig") {
+			keys[key.ID] = key.Public
+		}
+	}
+
+	p.keys, p.fetched = keys, time.Now()
+	return nil
+}
+
+// lookup Returns the loaded key of the 'kid', a provider publishing a single key may omit the header.
+func (p *Provider) lookup(kid string) *rsa.PublicKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+
+	return p.keys[kid]
+}
+
+func (p *Provider) key(ctx context.Context, jwksURI, kid string) (*rsa.PublicKey, error) {
+	if key := p.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	if err := p.fetchKeys(ctx, jwksURI); err != nil {
+		return nil, err
+	}
+
+	if key := p.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	return nil, keyring.ErrUnknownKey
+}
+
+// Verify Validates the signature, issuer, audience, expiration and nonce of the ID token.
+func (p *Provider) Verify(ctx context.Context, idToken, nonce string) (*Claims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, metadata.JwksURI, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg()}),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err.Error())
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, ErrInvalidNonce
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,217 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Duncan-Kiragu/Msaada-Backend/pkg/keyring"
+)
+
+// mockProvider In-process identity provider issuing one code per authorization.
+type mockProvider struct {
+	server   *httptest.Server
+	key      *keyring.Key
+	clientID string
+	secret   string
+	codes    map[string]url.Values
+	claims   func(nonce string) jwt.MapClaims
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := keyring.GenerateKey(1024)
+	assert.Nil(t, err)
+
+	mock := &mockProvider{key: key, clientID: "msaada", secret: "s3cret", codes: map[string]url.Values{}}
+	mock.claims = func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            mock.server.URL,
+			"aud":            mock.clientID,
+			"sub":            "staff-42",
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"nonce":          nonce,
+			"email":          "staff@partner.org",
+			"email_verified": true,
+			"name":           "Partner Staff",
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 mock.server.URL,
+			"authorization_endpoint": mock.server.URL + "/authorize",
+			"token_endpoint":         mock.server.URL + "/token",
+			"jwks_uri":               mock.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keyring.JWKS{Keys: []keyring.JWK{mock.key.JWK()}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		authorization, found := mock.codes[r.PostFormValue("code")]
+		delete(mock.codes, r.PostFormValue("code"))
+
+		if user != mock.clientID || pass != mock.secret || !found || r.PostFormValue("grant_type") != "authorization_code" ||
+			Challenge(r.PostFormValue("code_verifier")) != authorization.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, mock.claims(authorization.Get("nonce")))
+		token.Header["kid"] = mock.key.ID
+		signed, _ := token.SignedString(mock.key.Private)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+
+	mock.server = httptest.NewServer(mux)
+	t.Cleanup(mock.server.Close)
+	return mock
+}
+
+// authorize Simulates the user login at the provider, returning the code sent to the redirect.
+func (m *mockProvider) authorize(t *testing.T, address string) string {
+	parsed, err := url.Parse(address)
+	assert.Nil(t, err)
+
+	query := parsed.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, m.clientID, query.Get("client_id"))
+
+	m.codes["code-1"] = query
+	return "code-1"
+}
+
+func (m *mockProvider) provider() *Provider {
+	return New(Config{
+		Issuer:       m.server.URL,
+		ClientID:     m.clientID,
+		ClientSecret: m.secret,
+		RedirectURL:  "http://localhost/auth/oidc/callback",
+	})
+}
+
+// go test -run TestChallenge
+func TestChallenge(t *testing.T) {
+	// RFC 7636 appendix B example.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+
+	first, _ := RandomString()
+	second, _ := RandomString()
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, 43)
+}
+
+// go test -run TestLoginFlow
+func TestLoginFlow(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+
+	verifier, _ := RandomString()
+	address, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	assert.Nil(t, err)
+
+	idToken, err := provider.Exchange(ctx, mock.authorize(t, address), verifier)
+	assert.Nil(t, err)
+
+	claims, err := provider.Verify(ctx, idToken, "nonce-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "staff@partner.org", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "staff-42", claims.Subject)
+}
+
+// go test -run TestExchangeWrongVerifier
+func TestExchangeWrongVerifier(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+
+	verifier, _ := RandomString()
+	address, _ := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+
+	_, err := provider.Exchange(ctx, mock.authorize(t, address), "another-verifier")
+	assert.ErrorIs(t, err, ErrExchange)
+}
+
+// go test -run TestVerifyRejects
+func TestVerifyRejects(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+
+	sign := func(claims jwt.MapClaims, key *keyring.Key) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = key.ID
+		signed, _ := token.SignedString(key.Private)
+		return signed
+	}
+
+	_, err := provider.Verify(ctx, sign(mock.claims("nonce-1"), mock.key), "nonce-2")
+	assert.ErrorIs(t, err, ErrInvalidNonce)
+
+	claims := mock.claims("nonce-1")
+	claims["aud"] = "another-client"
+	_, err = provider.Verify(ctx, sign(claims, mock.key), "nonce-1")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	claims = mock.claims("nonce-1")
+	claims["iss"] = "https://attacker.example"
+	_, err = provider.Verify(ctx, sign(claims, mock.key), "nonce-1")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	claims = mock.claims("nonce-1")
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = provider.Verify(ctx, sign(claims, mock.key), "nonce-1")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	other, _ := keyring.GenerateKey(1024)
+	_, err = provider.Verify(ctx, sign(mock.claims("nonce-1"), other), "nonce-1")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// go test -run TestKeyRotation
+func TestKeyRotation(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+
+	sign := func() string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, mock.claims("nonce-1"))
+		token.Header["kid"] = mock.key.ID
+		signed, _ := token.SignedString(mock.key.Private)
+		return signed
+	}
+
+	_, err := provider.Verify(ctx, sign(), "nonce-1")
+	assert.Nil(t, err)
+
+	// A new key is only downloaded after the refresh interval.
+	mock.key, _ = keyring.GenerateKey(1024)
+	_, err = provider.Verify(ctx, sign(), "nonce-1")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	provider.fetched = time.Now().Add(-keysRefresh)
+	_, err = provider.Verify(ctx, sign(), "nonce-1")
+	assert.Nil(t, err)
+}
+
+// go test -run TestDiscoveryIssuerMismatch
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := New(Config{Issuer: mock.server.URL + "/", ClientID: mock.clientID})
+
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, ErrDiscovery)
+}
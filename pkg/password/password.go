@@ -0,0 +1,147 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minPersonalLength Shortest name part or email local part banned inside passwords.
+const minPersonalLength = 3
+
+var (
+	ErrTooShort = errors.New("password too short")
+	ErrTooLong  = errors.New("password too long")
+	ErrNoUpper  = errors.New("password without uppercase letter")
+	ErrNoLower  = errors.New("password without lowercase letter")
+	ErrNoDigit  = errors.New("password without digit")
+	ErrNoSymbol = errors.New("password without symbol")
+	ErrPersonal = errors.New("password contains personal data")
+	ErrCommon   = errors.New("password too common")
+
+	//go:embed common.txt
+	commonList string
+	common     = loadCommon(commonList)
+)
+
+// Policy Rules a password must satisfy, the zero value only rejects empty passwords.
+// 'MaxLength' counts bytes, bcrypt ignores anything after the 72nd.
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	BanPersonal   bool
+	BanCommon     bool
+}
+
+func loadCommon(list string) map[string]struct{} {
+	words := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words[strings.ToLower(word)] = struct{}{}
+		}
+	}
+
+	return words
+}
+
+// DefaultPolicy Returns the policy used when nothing is configured.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MinLength:    8,
+		MaxLength:    72,
+		RequireUpper: false,
+		RequireLower: true,
+		RequireDigit: true,
+		BanPersonal:  true,
+		BanCommon:    true,
+	}
+}
+
+// IsCommon Reports whether the password is in the embedded list of common and breached passwords.
+func IsCommon(password string) bool {
+	_, found := common[strings.ToLower(password)]
+	return found
+}
+
+// personalParts Splits names and emails into the lowercase parts that can not be used in a password.
+func personalParts(personal []string) []string {
+	parts := []string{}
+	for _, data := range personal {
+		data = strings.ToLower(data)
+		if at := strings.Index(data, "@"); at >= 0 {
+			data = data[:at]
+		}
+
+		for _, part := range strings.FieldsFunc(data, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if utf8.RuneCountInString(part) >= minPersonalLength {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	return parts
+}
+
+// Validate Returns the first rule of the policy broken by the password, 'personal' holds the
+// names and emails of the user that the password can not contain.
+func (p *Policy) Validate(password string, personal ...string) error {
+	length := utf8.RuneCountInString(password)
+	if length == 0 || length < p.MinLength {
+		return ErrTooShort
+	}
+
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return ErrTooLong
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return ErrNoUpper
+	case p.RequireLower && !lower:
+		return ErrNoLower
+	case p.RequireDigit && !digit:
+		return ErrNoDigit
+	case p.RequireSymbol && !symbol:
+		return ErrNoSymbol
+	}
+
+	if p.BanPersonal {
+		lowered := strings.ToLower(password)
+		for _, part := range personalParts(personal) {
+			if strings.Contains(lowered, part) {
+				return ErrPersonal
+			}
+		}
+	}
+
+	if p.BanCommon && IsCommon(password) {
+		return ErrCommon
+	}
+
+	return nil
+}
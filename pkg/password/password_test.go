@@ -0,0 +1,56 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestPolicyLength
+func TestPolicyLength(t *testing.T) {
+	policy := &Policy{MinLength: 8, MaxLength: 12}
+
+	assert.ErrorIs(t, policy.Validate(""), ErrTooShort)
+	assert.ErrorIs(t, policy.Validate("abc"), ErrTooShort)
+	assert.ErrorIs(t, policy.Validate("abcdefghijklm"), ErrTooLong)
+	assert.Nil(t, policy.Validate("abcdefgh"))
+}
+
+// go test -run TestPolicyClasses
+func TestPolicyClasses(t *testing.T) {
+	policy := &Policy{RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	assert.ErrorIs(t, policy.Validate("abcd1!"), ErrNoUpper)
+	assert.ErrorIs(t, policy.Validate("ABCD1!"), ErrNoLower)
+	assert.ErrorIs(t, policy.Validate("Abcde!"), ErrNoDigit)
+	assert.ErrorIs(t, policy.Validate("Abcde1"), ErrNoSymbol)
+	assert.Nil(t, policy.Validate("Abcd1!"))
+}
+
+// go test -run TestPolicyPersonal
+func TestPolicyPersonal(t *testing.T) {
+	policy := &Policy{BanPersonal: true}
+
+	assert.ErrorIs(t, policy.Validate("xxCena2026", "John Cena", "jc@email.com"), ErrPersonal)
+	assert.ErrorIs(t, policy.Validate("john.cena.rules", "Someone", "john.cena@email.com"), ErrPersonal)
+	assert.Nil(t, policy.Validate("email-domain", "Jo", "jc@email.com"))
+}
+
+// go test -run TestPolicyCommon
+func TestPolicyCommon(t *testing.T) {
+	policy := &Policy{BanCommon: true}
+
+	assert.True(t, IsCommon("Password123"))
+	assert.ErrorIs(t, policy.Validate("QWERTY123"), ErrCommon)
+	assert.Nil(t, policy.Validate("correct horse battery staple"))
+}
+
+// go test -run TestDefaultPolicy
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	assert.ErrorIs(t, policy.Validate("short1"), ErrTooShort)
+	assert.ErrorIs(t, policy.Validate("password1"), ErrCommon)
+	assert.ErrorIs(t, policy.Validate("longpassword"), ErrNoDigit)
+	assert.Nil(t, policy.Validate("river7stone"))
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// Store Counter of the requests of each key within fixed windows, shared by the limiters.
+	Store interface {
+		// Hit Counts a request of the key, returning the count of its current window and when it ends.
+		Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+	}
+
+	// Policy Named limit of 'Limit' requests per 'Window', the name keeps the counters of the
+	// policies apart in a shared store.
+	Policy struct {
+		Name   string
+		Limit  int
+		Window time.Duration
+	}
+
+	// Result State of the window after a request.
+	Result struct {
+		Allowed   bool
+		Limit     int
+		Remaining int
+		Reset     time.Time
+	}
+
+	// Limiter Applies a policy with the counters of a store.
+	Limiter struct {
+		policy Policy
+		store  Store
+	}
+)
+
+// New Creates the limiter of the policy.
+func New(policy Policy, store Store) *Limiter {
+	return &Limiter{policy: policy, store: store}
+}
+
+// Policy Returns the policy applied by the limiter.
+func (s *Limiter) Policy() Policy {
+	return s.policy
+}
+
+// Allow Counts a request of the key, reporting whether it is within the limit.
+func (s *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	count, reset, err := s.store.Hit(ctx, s.policy.Name+":"+key, s.policy.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Allowed:   count <= s.policy.Limit,
+		Limit:     s.policy.Limit,
+		Remaining: max(s.policy.Limit-count, 0),
+		Reset:     reset,
+	}, nil
+}
+
+// String Returns the policy in the format of the 'RateLimit-Policy' header.
+func (s Policy) String() string {
+	return fmt.Sprintf("%d;w=%d", s.Limit, int(s.Window.Seconds()))
+}
+
+type window struct {
+	count int
+	reset time.Time
+}
+
+// MemoryStore Store of a single instance, the ended windows are dropped while counting.
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]*window
+	now     func() time.Time
+	swept   time.Time
+}
+
+// NewMemoryStore Creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: map[string]*window{}, now: time.Now}
+}
+
+// Hit Implementation of 'Hit'.
+func (s *MemoryStore) Hit(_ context.Context, key string, length time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.swept) > time.Minute {
+		for key, current := range s.windows {
+			if !current.reset.After(now) {
+				delete(s.windows, key)
+			}
+		}
+		s.swept = now
+	}
+
+	current, found := s.windows[key]
+	if !found || !current.reset.After(now) {
+		current = &window{reset: now.Add(length)}
+		s.windows[key] = current
+	}
+	current.count++
+
+	return current.count, current.reset, nil
+}
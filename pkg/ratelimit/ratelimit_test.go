@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestLimiterAllow
+func TestLimiterAllow(t *testing.T) {
+	limiter := New(Policy{Name: "auth", Limit: 3, Window: time.Minute}, NewMemoryStore())
+	ctx := context.Background()
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := limiter.Allow(ctx, "ip:10.0.0.1")
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, remaining, result.Remaining)
+		assert.Equal(t, 3, result.Limit)
+	}
+
+	result, err := limiter.Allow(ctx, "ip:10.0.0.1")
+	assert.Nil(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Other keys have their own counter.
+	result, _ = limiter.Allow(ctx, "ip:10.0.0.2")
+	assert.True(t, result.Allowed)
+}
+
+// go test -run TestLimiterPolicies
+func TestLimiterPolicies(t *testing.T) {
+	store := NewMemoryStore()
+	strict := New(Policy{Name: "auth", Limit: 1, Window: time.Minute}, store)
+	loose := New(Policy{Name: "api", Limit: 10, Window: time.Minute}, store)
+	ctx := context.Background()
+
+	_, _ = strict.Allow(ctx, "user:1")
+	result, _ := strict.Allow(ctx, "user:1")
+	assert.False(t, result.Allowed)
+
+	result, _ = loose.Allow(ctx, "user:1")
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 9, result.Remaining)
+}
+
+// go test -run TestMemoryStoreWindow
+func TestMemoryStoreWindow(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	count, reset, _ := store.Hit(ctx, "key", time.Minute)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, now.Add(time.Minute), reset)
+
+	now = now.Add(30 * time.Second)
+	count, reset, _ = store.Hit(ctx, "key", time.Minute)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, now.Add(30*time.Second), reset)
+
+	// A new window starts once the previous one ended, dropping the ended windows.
+	now = now.Add(2 * time.Minute)
+	_, _, _ = store.Hit(ctx, "other", time.Minute)
+	assert.Len(t, store.windows, 1)
+
+	count, _, _ = store.Hit(ctx, "key", time.Minute)
+	assert.Equal(t, 1, count)
+}
+
+// go test -run TestPolicyString
+func TestPolicyString(t *testing.T) {
+	assert.Equal(t, "20;w=60", Policy{Name: "auth", Limit: 20, Window: time.Minute}.String())
+}
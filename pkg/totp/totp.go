@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits Length of the generated codes.
+	Digits = 6
+	// Period Seconds each code remains valid.
+	Period = 30
+	// Skew Number of periods before and after the current one also accepted.
+	Skew = 1
+)
+
+var (
+	ErrInvalidSecret = errors.New("invalid totp secret")
+
+	encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// GenerateSecret Returns a random 160 bits secret encoded in base32, as expected by authenticator apps.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// Code Computes the RFC 6238 code of the secret at the moment.
+func Code(secret string, at time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", ErrInvalidSecret
+	}
+
+	return hotp(key, uint64(at.Unix()/Period)), nil
+}
+
+// Validate Reports whether the code matches the secret at the moment, tolerating the clock skew.
+func Validate(secret, code string, at time.Time) bool {
+	_, ok := Match(secret, code, at)
+	return ok
+}
+
+// Match Returns the time step of the code when it matches the secret at the moment, tolerating the
+// clock skew. Refusing the steps already accepted keeps a code from being replayed.
+func Match(secret, code string, at time.Time) (int64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	for i := -Skew; i <= Skew; i++ {
+		moment := at.Add(time.Duration(i*Period) * time.Second)
+		expected, err := Code(secret, moment)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return moment.Unix() / Period, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI Returns the 'otpauth' provisioning URI, usually rendered as a QR code for authenticator apps.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(Period))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// hotp Computes the RFC 4226 code of the key for the counter.
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
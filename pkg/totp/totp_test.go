@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 appendix B secret for SHA1.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// go test -run TestCodeRFCVectors
+func TestCodeRFCVectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := Code(rfcSecret, time.Unix(unix, 0))
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+// go test -run TestValidate
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	now := time.Now()
+	code, err := Code(secret, now)
+	assert.Nil(t, err)
+
+	assert.True(t, Validate(secret, code, now))
+	assert.True(t, Validate(secret, code, now.Add(Period*time.Second)))
+	assert.False(t, Validate(secret, code, now.Add(3*Period*time.Second)))
+	assert.False(t, Validate(secret, "12345", now))
+	assert.False(t, Validate("not base32!", code, now))
+}
+
+// go test -run TestMatch
+func TestMatch(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	now := time.Unix(1700000000, 0)
+	code, err := Code(secret, now)
+	assert.Nil(t, err)
+
+	step, ok := Match(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/Period, step)
+
+	// The step is the one of the code, not of the moment of the check.
+	step, ok = Match(secret, code, now.Add(Period*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/Period, step)
+
+	_, ok = Match(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+// go test -run TestURI
+func TestURI(t *testing.T) {
+	uri := URI("MSAADA", "john.cena@email.com", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/MSAADA:john.cena@email.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=MSAADA")
+}